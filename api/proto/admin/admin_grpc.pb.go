@@ -0,0 +1,463 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: api/proto/admin/admin.proto
+
+package adminpb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AdminService_GetGameStatus_FullMethodName = "/adminpb.AdminService/GetGameStatus"
+	AdminService_ListCowboys_FullMethodName   = "/adminpb.AdminService/ListCowboys"
+	AdminService_StartNow_FullMethodName      = "/adminpb.AdminService/StartNow"
+	AdminService_Abort_FullMethodName         = "/adminpb.AdminService/Abort"
+	AdminService_Pause_FullMethodName         = "/adminpb.AdminService/Pause"
+	AdminService_Resume_FullMethodName        = "/adminpb.AdminService/Resume"
+	AdminService_SetHealth_FullMethodName     = "/adminpb.AdminService/SetHealth"
+	AdminService_Revive_FullMethodName        = "/adminpb.AdminService/Revive"
+	AdminService_Kick_FullMethodName          = "/adminpb.AdminService/Kick"
+	AdminService_Smite_FullMethodName         = "/adminpb.AdminService/Smite"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// GetGameStatus returns the game phase and the state of every cowboy
+	GetGameStatus(ctx context.Context, in *GetGameStatusRequest, opts ...grpc.CallOption) (*GetGameStatusResponse, error)
+	// ListCowboys returns the roster
+	ListCowboys(ctx context.Context, in *ListCowboysRequest, opts ...grpc.CallOption) (*ListCowboysResponse, error)
+	// StartNow starts the shootout with the ready cowboys without waiting for the others
+	StartNow(ctx context.Context, in *StartNowRequest, opts ...grpc.CallOption) (*StartNowResponse, error)
+	// Abort ends the game without a winner
+	Abort(ctx context.Context, in *AbortRequest, opts ...grpc.CallOption) (*AbortResponse, error)
+	// Pause freezes a running game, cowboys stop shooting and reject damage until it's resumed
+	Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error)
+	// Resume continues a paused game
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error)
+	// SetHealth sets the health of an alive cowboy, 0 kills it
+	SetHealth(ctx context.Context, in *SetHealthRequest, opts ...grpc.CallOption) (*InterventionResponse, error)
+	// Revive brings a dead cowboy back into a running or paused game
+	Revive(ctx context.Context, in *ReviveRequest, opts ...grpc.CallOption) (*InterventionResponse, error)
+	// Kick removes an alive cowboy from the game as if it forfeited
+	Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*InterventionResponse, error)
+	// Smite deals damage to an alive cowboy without a shooter
+	Smite(ctx context.Context, in *SmiteRequest, opts ...grpc.CallOption) (*InterventionResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetGameStatus(ctx context.Context, in *GetGameStatusRequest, opts ...grpc.CallOption) (*GetGameStatusResponse, error) {
+	out := new(GetGameStatusResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetGameStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListCowboys(ctx context.Context, in *ListCowboysRequest, opts ...grpc.CallOption) (*ListCowboysResponse, error) {
+	out := new(ListCowboysResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListCowboys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) StartNow(ctx context.Context, in *StartNowRequest, opts ...grpc.CallOption) (*StartNowResponse, error) {
+	out := new(StartNowResponse)
+	err := c.cc.Invoke(ctx, AdminService_StartNow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Abort(ctx context.Context, in *AbortRequest, opts ...grpc.CallOption) (*AbortResponse, error) {
+	out := new(AbortResponse)
+	err := c.cc.Invoke(ctx, AdminService_Abort_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error) {
+	out := new(PauseResponse)
+	err := c.cc.Invoke(ctx, AdminService_Pause_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error) {
+	out := new(ResumeResponse)
+	err := c.cc.Invoke(ctx, AdminService_Resume_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetHealth(ctx context.Context, in *SetHealthRequest, opts ...grpc.CallOption) (*InterventionResponse, error) {
+	out := new(InterventionResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetHealth_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Revive(ctx context.Context, in *ReviveRequest, opts ...grpc.CallOption) (*InterventionResponse, error) {
+	out := new(InterventionResponse)
+	err := c.cc.Invoke(ctx, AdminService_Revive_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*InterventionResponse, error) {
+	out := new(InterventionResponse)
+	err := c.cc.Invoke(ctx, AdminService_Kick_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Smite(ctx context.Context, in *SmiteRequest, opts ...grpc.CallOption) (*InterventionResponse, error) {
+	out := new(InterventionResponse)
+	err := c.cc.Invoke(ctx, AdminService_Smite_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	// GetGameStatus returns the game phase and the state of every cowboy
+	GetGameStatus(context.Context, *GetGameStatusRequest) (*GetGameStatusResponse, error)
+	// ListCowboys returns the roster
+	ListCowboys(context.Context, *ListCowboysRequest) (*ListCowboysResponse, error)
+	// StartNow starts the shootout with the ready cowboys without waiting for the others
+	StartNow(context.Context, *StartNowRequest) (*StartNowResponse, error)
+	// Abort ends the game without a winner
+	Abort(context.Context, *AbortRequest) (*AbortResponse, error)
+	// Pause freezes a running game, cowboys stop shooting and reject damage until it's resumed
+	Pause(context.Context, *PauseRequest) (*PauseResponse, error)
+	// Resume continues a paused game
+	Resume(context.Context, *ResumeRequest) (*ResumeResponse, error)
+	// SetHealth sets the health of an alive cowboy, 0 kills it
+	SetHealth(context.Context, *SetHealthRequest) (*InterventionResponse, error)
+	// Revive brings a dead cowboy back into a running or paused game
+	Revive(context.Context, *ReviveRequest) (*InterventionResponse, error)
+	// Kick removes an alive cowboy from the game as if it forfeited
+	Kick(context.Context, *KickRequest) (*InterventionResponse, error)
+	// Smite deals damage to an alive cowboy without a shooter
+	Smite(context.Context, *SmiteRequest) (*InterventionResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) GetGameStatus(context.Context, *GetGameStatusRequest) (*GetGameStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameStatus not implemented")
+}
+func (UnimplementedAdminServiceServer) ListCowboys(context.Context, *ListCowboysRequest) (*ListCowboysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCowboys not implemented")
+}
+func (UnimplementedAdminServiceServer) StartNow(context.Context, *StartNowRequest) (*StartNowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartNow not implemented")
+}
+func (UnimplementedAdminServiceServer) Abort(context.Context, *AbortRequest) (*AbortResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Abort not implemented")
+}
+func (UnimplementedAdminServiceServer) Pause(context.Context, *PauseRequest) (*PauseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedAdminServiceServer) Resume(context.Context, *ResumeRequest) (*ResumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedAdminServiceServer) SetHealth(context.Context, *SetHealthRequest) (*InterventionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHealth not implemented")
+}
+func (UnimplementedAdminServiceServer) Revive(context.Context, *ReviveRequest) (*InterventionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revive not implemented")
+}
+func (UnimplementedAdminServiceServer) Kick(context.Context, *KickRequest) (*InterventionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kick not implemented")
+}
+func (UnimplementedAdminServiceServer) Smite(context.Context, *SmiteRequest) (*InterventionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Smite not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_GetGameStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetGameStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetGameStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetGameStatus(ctx, req.(*GetGameStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListCowboys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCowboysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListCowboys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListCowboys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListCowboys(ctx, req.(*ListCowboysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StartNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StartNow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartNow(ctx, req.(*StartNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Abort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Abort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Abort_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Abort(ctx, req.(*AbortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Pause(ctx, req.(*PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetHealth(ctx, req.(*SetHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Revive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Revive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Revive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Revive(ctx, req.(*ReviveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Kick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Kick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Kick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Kick(ctx, req.(*KickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Smite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SmiteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Smite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Smite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Smite(ctx, req.(*SmiteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "adminpb.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetGameStatus",
+			Handler:    _AdminService_GetGameStatus_Handler,
+		},
+		{
+			MethodName: "ListCowboys",
+			Handler:    _AdminService_ListCowboys_Handler,
+		},
+		{
+			MethodName: "StartNow",
+			Handler:    _AdminService_StartNow_Handler,
+		},
+		{
+			MethodName: "Abort",
+			Handler:    _AdminService_Abort_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _AdminService_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _AdminService_Resume_Handler,
+		},
+		{
+			MethodName: "SetHealth",
+			Handler:    _AdminService_SetHealth_Handler,
+		},
+		{
+			MethodName: "Revive",
+			Handler:    _AdminService_Revive_Handler,
+		},
+		{
+			MethodName: "Kick",
+			Handler:    _AdminService_Kick_Handler,
+		},
+		{
+			MethodName: "Smite",
+			Handler:    _AdminService_Smite_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/proto/admin/admin.proto",
+}
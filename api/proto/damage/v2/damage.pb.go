@@ -0,0 +1,455 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.21.12
+// source: api/proto/damage/v2/damage.proto
+
+package damagev2pb
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Outcome int32
+
+const (
+	Outcome_OUTCOME_UNSPECIFIED Outcome = 0
+	// the target was hit and is still alive
+	Outcome_OUTCOME_HIT Outcome = 1
+	// the shot killed the target
+	Outcome_OUTCOME_KILLED Outcome = 2
+	// the target was already dead, the shot wasn't applied
+	Outcome_OUTCOME_TARGET_DEAD Outcome = 3
+)
+
+// Enum value maps for Outcome.
+var (
+	Outcome_name = map[int32]string{
+		0: "OUTCOME_UNSPECIFIED",
+		1: "OUTCOME_HIT",
+		2: "OUTCOME_KILLED",
+		3: "OUTCOME_TARGET_DEAD",
+	}
+	Outcome_value = map[string]int32{
+		"OUTCOME_UNSPECIFIED": 0,
+		"OUTCOME_HIT":         1,
+		"OUTCOME_KILLED":      2,
+		"OUTCOME_TARGET_DEAD": 3,
+	}
+)
+
+func (x Outcome) Enum() *Outcome {
+	p := new(Outcome)
+	*p = x
+	return p
+}
+
+func (x Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_damage_v2_damage_proto_enumTypes[0].Descriptor()
+}
+
+func (Outcome) Type() protoreflect.EnumType {
+	return &file_api_proto_damage_v2_damage_proto_enumTypes[0]
+}
+
+func (x Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Outcome.Descriptor instead.
+func (Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_damage_v2_damage_proto_rawDescGZIP(), []int{0}
+}
+
+type DamageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From   int64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	Damage int64 `protobuf:"varint,2,opt,name=damage,proto3" json:"damage,omitempty"`
+	// seq identifies the shot on a stream, unused in unary calls
+	Seq uint64 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	// nonce, sent_at (unix nanoseconds) and signature authenticate the shooter when shot signing is enabled
+	Nonce     string `protobuf:"bytes,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	SentAt    int64  `protobuf:"varint,5,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *DamageRequest) Reset() {
+	*x = DamageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_damage_v2_damage_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DamageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DamageRequest) ProtoMessage() {}
+
+func (x *DamageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_damage_v2_damage_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DamageRequest.ProtoReflect.Descriptor instead.
+func (*DamageRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_damage_v2_damage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DamageRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *DamageRequest) GetDamage() int64 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+func (x *DamageRequest) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *DamageRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *DamageRequest) GetSentAt() int64 {
+	if x != nil {
+		return x.SentAt
+	}
+	return 0
+}
+
+func (x *DamageRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type DamageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Outcome         Outcome `protobuf:"varint,1,opt,name=outcome,proto3,enum=damagepb.v2.Outcome" json:"outcome,omitempty"`
+	RemainingHealth int64   `protobuf:"varint,2,opt,name=remaining_health,json=remainingHealth,proto3" json:"remaining_health,omitempty"`
+	TargetDead      bool    `protobuf:"varint,3,opt,name=target_dead,json=targetDead,proto3" json:"target_dead,omitempty"`
+}
+
+func (x *DamageResponse) Reset() {
+	*x = DamageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_damage_v2_damage_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DamageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DamageResponse) ProtoMessage() {}
+
+func (x *DamageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_damage_v2_damage_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DamageResponse.ProtoReflect.Descriptor instead.
+func (*DamageResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_damage_v2_damage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DamageResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+func (x *DamageResponse) GetRemainingHealth() int64 {
+	if x != nil {
+		return x.RemainingHealth
+	}
+	return 0
+}
+
+func (x *DamageResponse) GetTargetDead() bool {
+	if x != nil {
+		return x.TargetDead
+	}
+	return false
+}
+
+type DamageAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq uint64 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	// code and message of the shot's gRPC status, code 0 means the shot was applied
+	Code    int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// response is set when code is 0
+	Response *DamageResponse `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *DamageAck) Reset() {
+	*x = DamageAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_damage_v2_damage_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DamageAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DamageAck) ProtoMessage() {}
+
+func (x *DamageAck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_damage_v2_damage_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DamageAck.ProtoReflect.Descriptor instead.
+func (*DamageAck) Descriptor() ([]byte, []int) {
+	return file_api_proto_damage_v2_damage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DamageAck) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *DamageAck) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *DamageAck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DamageAck) GetResponse() *DamageResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+var File_api_proto_damage_v2_damage_proto protoreflect.FileDescriptor
+
+var file_api_proto_damage_v2_damage_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x61, 0x6d, 0x61,
+	0x67, 0x65, 0x2f, 0x76, 0x32, 0x2f, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0b, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x32, 0x22,
+	0x9a, 0x01, 0x0a, 0x0d, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x73, 0x65, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12,
+	0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x8c, 0x01, 0x0a,
+	0x0e, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2e, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x14, 0x2e, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x32, 0x2e, 0x4f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x65, 0x61,
+	0x6c, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x72, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x65, 0x61, 0x64, 0x22, 0x84, 0x01, 0x0a, 0x09,
+	0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x61,
+	0x6d, 0x61, 0x67, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2a, 0x60, 0x0a, 0x07, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x17, 0x0a,
+	0x13, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d,
+	0x45, 0x5f, 0x48, 0x49, 0x54, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x55, 0x54, 0x43, 0x4f,
+	0x4d, 0x45, 0x5f, 0x4b, 0x49, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x4f,
+	0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x5f, 0x44, 0x45,
+	0x41, 0x44, 0x10, 0x03, 0x32, 0xa1, 0x01, 0x0a, 0x0d, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x2e, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65,
+	0x70, 0x62, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x70, 0x62, 0x2e, 0x76,
+	0x32, 0x2e, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x46, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65,
+	0x12, 0x1a, 0x2e, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x32, 0x2e, 0x44,
+	0x61, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x64,
+	0x61, 0x6d, 0x61, 0x67, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x61, 0x6d, 0x61, 0x67,
+	0x65, 0x41, 0x63, 0x6b, 0x28, 0x01, 0x30, 0x01, 0x42, 0x29, 0x5a, 0x27, 0x77, 0x69, 0x6c, 0x64,
+	0x77, 0x65, 0x73, 0x74, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64,
+	0x61, 0x6d, 0x61, 0x67, 0x65, 0x2f, 0x76, 0x32, 0x3b, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x76,
+	0x32, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_proto_damage_v2_damage_proto_rawDescOnce sync.Once
+	file_api_proto_damage_v2_damage_proto_rawDescData = file_api_proto_damage_v2_damage_proto_rawDesc
+)
+
+func file_api_proto_damage_v2_damage_proto_rawDescGZIP() []byte {
+	file_api_proto_damage_v2_damage_proto_rawDescOnce.Do(func() {
+		file_api_proto_damage_v2_damage_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_proto_damage_v2_damage_proto_rawDescData)
+	})
+	return file_api_proto_damage_v2_damage_proto_rawDescData
+}
+
+var file_api_proto_damage_v2_damage_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_proto_damage_v2_damage_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_proto_damage_v2_damage_proto_goTypes = []interface{}{
+	(Outcome)(0),           // 0: damagepb.v2.Outcome
+	(*DamageRequest)(nil),  // 1: damagepb.v2.DamageRequest
+	(*DamageResponse)(nil), // 2: damagepb.v2.DamageResponse
+	(*DamageAck)(nil),      // 3: damagepb.v2.DamageAck
+}
+var file_api_proto_damage_v2_damage_proto_depIdxs = []int32{
+	0, // 0: damagepb.v2.DamageResponse.outcome:type_name -> damagepb.v2.Outcome
+	2, // 1: damagepb.v2.DamageAck.response:type_name -> damagepb.v2.DamageResponse
+	1, // 2: damagepb.v2.DamageService.ReceiveDamage:input_type -> damagepb.v2.DamageRequest
+	1, // 3: damagepb.v2.DamageService.StreamDamage:input_type -> damagepb.v2.DamageRequest
+	2, // 4: damagepb.v2.DamageService.ReceiveDamage:output_type -> damagepb.v2.DamageResponse
+	3, // 5: damagepb.v2.DamageService.StreamDamage:output_type -> damagepb.v2.DamageAck
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_damage_v2_damage_proto_init() }
+func file_api_proto_damage_v2_damage_proto_init() {
+	if File_api_proto_damage_v2_damage_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_proto_damage_v2_damage_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DamageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_damage_v2_damage_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DamageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_damage_v2_damage_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DamageAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_proto_damage_v2_damage_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_proto_damage_v2_damage_proto_goTypes,
+		DependencyIndexes: file_api_proto_damage_v2_damage_proto_depIdxs,
+		EnumInfos:         file_api_proto_damage_v2_damage_proto_enumTypes,
+		MessageInfos:      file_api_proto_damage_v2_damage_proto_msgTypes,
+	}.Build()
+	File_api_proto_damage_v2_damage_proto = out.File
+	file_api_proto_damage_v2_damage_proto_rawDesc = nil
+	file_api_proto_damage_v2_damage_proto_goTypes = nil
+	file_api_proto_damage_v2_damage_proto_depIdxs = nil
+}
@@ -0,0 +1,182 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: api/proto/damage/v2/damage.proto
+
+package damagev2pb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DamageService_ReceiveDamage_FullMethodName = "/damagepb.v2.DamageService/ReceiveDamage"
+	DamageService_StreamDamage_FullMethodName  = "/damagepb.v2.DamageService/StreamDamage"
+)
+
+// DamageServiceClient is the client API for DamageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DamageServiceClient interface {
+	ReceiveDamage(ctx context.Context, in *DamageRequest, opts ...grpc.CallOption) (*DamageResponse, error)
+	// StreamDamage carries many shots over one stream, every shot is acknowledged with its sequence number
+	StreamDamage(ctx context.Context, opts ...grpc.CallOption) (DamageService_StreamDamageClient, error)
+}
+
+type damageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDamageServiceClient(cc grpc.ClientConnInterface) DamageServiceClient {
+	return &damageServiceClient{cc}
+}
+
+func (c *damageServiceClient) ReceiveDamage(ctx context.Context, in *DamageRequest, opts ...grpc.CallOption) (*DamageResponse, error) {
+	out := new(DamageResponse)
+	err := c.cc.Invoke(ctx, DamageService_ReceiveDamage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *damageServiceClient) StreamDamage(ctx context.Context, opts ...grpc.CallOption) (DamageService_StreamDamageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DamageService_ServiceDesc.Streams[0], DamageService_StreamDamage_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &damageServiceStreamDamageClient{stream}
+	return x, nil
+}
+
+type DamageService_StreamDamageClient interface {
+	Send(*DamageRequest) error
+	Recv() (*DamageAck, error)
+	grpc.ClientStream
+}
+
+type damageServiceStreamDamageClient struct {
+	grpc.ClientStream
+}
+
+func (x *damageServiceStreamDamageClient) Send(m *DamageRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *damageServiceStreamDamageClient) Recv() (*DamageAck, error) {
+	m := new(DamageAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DamageServiceServer is the server API for DamageService service.
+// All implementations must embed UnimplementedDamageServiceServer
+// for forward compatibility
+type DamageServiceServer interface {
+	ReceiveDamage(context.Context, *DamageRequest) (*DamageResponse, error)
+	// StreamDamage carries many shots over one stream, every shot is acknowledged with its sequence number
+	StreamDamage(DamageService_StreamDamageServer) error
+	mustEmbedUnimplementedDamageServiceServer()
+}
+
+// UnimplementedDamageServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDamageServiceServer struct {
+}
+
+func (UnimplementedDamageServiceServer) ReceiveDamage(context.Context, *DamageRequest) (*DamageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveDamage not implemented")
+}
+func (UnimplementedDamageServiceServer) StreamDamage(DamageService_StreamDamageServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDamage not implemented")
+}
+func (UnimplementedDamageServiceServer) mustEmbedUnimplementedDamageServiceServer() {}
+
+// UnsafeDamageServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DamageServiceServer will
+// result in compilation errors.
+type UnsafeDamageServiceServer interface {
+	mustEmbedUnimplementedDamageServiceServer()
+}
+
+func RegisterDamageServiceServer(s grpc.ServiceRegistrar, srv DamageServiceServer) {
+	s.RegisterService(&DamageService_ServiceDesc, srv)
+}
+
+func _DamageService_ReceiveDamage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DamageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DamageServiceServer).ReceiveDamage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DamageService_ReceiveDamage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DamageServiceServer).ReceiveDamage(ctx, req.(*DamageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DamageService_StreamDamage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DamageServiceServer).StreamDamage(&damageServiceStreamDamageServer{stream})
+}
+
+type DamageService_StreamDamageServer interface {
+	Send(*DamageAck) error
+	Recv() (*DamageRequest, error)
+	grpc.ServerStream
+}
+
+type damageServiceStreamDamageServer struct {
+	grpc.ServerStream
+}
+
+func (x *damageServiceStreamDamageServer) Send(m *DamageAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *damageServiceStreamDamageServer) Recv() (*DamageRequest, error) {
+	m := new(DamageRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DamageService_ServiceDesc is the grpc.ServiceDesc for DamageService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DamageService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "damagepb.v2.DamageService",
+	HandlerType: (*DamageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReceiveDamage",
+			Handler:    _DamageService_ReceiveDamage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDamage",
+			Handler:       _DamageService_StreamDamage_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/proto/damage/v2/damage.proto",
+}
@@ -1,4 +1,8 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: api/proto/shootout/shootout.proto
 
 package shootoutpb
 
@@ -16,11 +20,18 @@ import (
 // Requires gRPC-Go v1.32.0 or later.
 const _ = grpc.SupportPackageIsVersion7
 
+const (
+	ShootoutService_ReceiveShootoutTime_FullMethodName = "/shootoutpb.ShootoutService/ReceiveShootoutTime"
+	ShootoutService_SyncClock_FullMethodName           = "/shootoutpb.ShootoutService/SyncClock"
+)
+
 // ShootoutServiceClient is the client API for ShootoutService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ShootoutServiceClient interface {
 	ReceiveShootoutTime(ctx context.Context, in *ReceiveShootoutTimeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SyncClock is an NTP-style exchange letting the controller estimate the clock offset of a cowboy
+	SyncClock(ctx context.Context, in *SyncClockRequest, opts ...grpc.CallOption) (*SyncClockResponse, error)
 }
 
 type shootoutServiceClient struct {
@@ -33,7 +44,16 @@ func NewShootoutServiceClient(cc grpc.ClientConnInterface) ShootoutServiceClient
 
 func (c *shootoutServiceClient) ReceiveShootoutTime(ctx context.Context, in *ReceiveShootoutTimeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/shootoutpb.ShootoutService/ReceiveShootoutTime", in, out, opts...)
+	err := c.cc.Invoke(ctx, ShootoutService_ReceiveShootoutTime_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shootoutServiceClient) SyncClock(ctx context.Context, in *SyncClockRequest, opts ...grpc.CallOption) (*SyncClockResponse, error) {
+	out := new(SyncClockResponse)
+	err := c.cc.Invoke(ctx, ShootoutService_SyncClock_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +65,8 @@ func (c *shootoutServiceClient) ReceiveShootoutTime(ctx context.Context, in *Rec
 // for forward compatibility
 type ShootoutServiceServer interface {
 	ReceiveShootoutTime(context.Context, *ReceiveShootoutTimeRequest) (*emptypb.Empty, error)
+	// SyncClock is an NTP-style exchange letting the controller estimate the clock offset of a cowboy
+	SyncClock(context.Context, *SyncClockRequest) (*SyncClockResponse, error)
 	mustEmbedUnimplementedShootoutServiceServer()
 }
 
@@ -55,6 +77,9 @@ type UnimplementedShootoutServiceServer struct {
 func (UnimplementedShootoutServiceServer) ReceiveShootoutTime(context.Context, *ReceiveShootoutTimeRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReceiveShootoutTime not implemented")
 }
+func (UnimplementedShootoutServiceServer) SyncClock(context.Context, *SyncClockRequest) (*SyncClockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncClock not implemented")
+}
 func (UnimplementedShootoutServiceServer) mustEmbedUnimplementedShootoutServiceServer() {}
 
 // UnsafeShootoutServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -78,7 +103,7 @@ func _ShootoutService_ReceiveShootoutTime_Handler(srv interface{}, ctx context.C
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/shootoutpb.ShootoutService/ReceiveShootoutTime",
+		FullMethod: ShootoutService_ReceiveShootoutTime_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(ShootoutServiceServer).ReceiveShootoutTime(ctx, req.(*ReceiveShootoutTimeRequest))
@@ -86,6 +111,24 @@ func _ShootoutService_ReceiveShootoutTime_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShootoutService_SyncClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShootoutServiceServer).SyncClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShootoutService_SyncClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShootoutServiceServer).SyncClock(ctx, req.(*SyncClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShootoutService_ServiceDesc is the grpc.ServiceDesc for ShootoutService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -97,6 +140,10 @@ var ShootoutService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReceiveShootoutTime",
 			Handler:    _ShootoutService_ReceiveShootoutTime_Handler,
 		},
+		{
+			MethodName: "SyncClock",
+			Handler:    _ShootoutService_SyncClock_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/shootout/shootout.proto",
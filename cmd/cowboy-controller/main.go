@@ -1,12 +1,39 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 	"wildwest/internal/broadcastdispatcher"
+	"wildwest/internal/datastore"
+	"wildwest/internal/events"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/handlers/adminhandler"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/intervention"
+	"wildwest/internal/metrics"
+	"wildwest/internal/resolver"
+	"wildwest/internal/schedule"
+	"wildwest/internal/series"
+	"wildwest/internal/tlsconfig"
 	"wildwest/internal/utils"
+	"wildwest/internal/winner"
 
 	"github.com/caarlos0/env/v6"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	adminpb "wildwest/api/proto/admin"
 )
 
 func main() {
@@ -20,14 +47,251 @@ func main() {
 		logger.Fatal("parse environment", zap.Error(err))
 	}
 
-	// start readiness server
+	// check what to do when not all cowboys get ready in time and how to start them
+	switch envConfig.ReadinessTimeoutPolicy {
+	case utils.ReadinessTimeoutPolicyStart, utils.ReadinessTimeoutPolicyAbort:
+	default:
+		logger.Fatal("unknown readiness timeout policy", zap.String("readiness_timeout_policy", envConfig.ReadinessTimeoutPolicy))
+	}
+
+	switch envConfig.StartMode {
+	case utils.StartModePush, utils.StartModePull:
+	default:
+		logger.Fatal("unknown start mode", zap.String("start_mode", envConfig.StartMode))
+	}
+
+	// parse the schedule the games recur on
+	var gameSchedule *schedule.Schedule
+	if envConfig.Schedule != "" {
+		gameSchedule, err = schedule.Parse(envConfig.Schedule)
+		if err != nil {
+			logger.Fatal("parse schedule", zap.Error(err))
+		}
+	}
+
+	// stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// load mTLS credentials
+	creds, err := tlsconfig.Load(tlsconfig.Config{
+		Enabled:  envConfig.TLSEnabled,
+		CertFile: envConfig.TLSCertFile,
+		KeyFile:  envConfig.TLSKeyFile,
+		CAFile:   envConfig.TLSCAFile,
+	})
+	if err != nil {
+		logger.Fatal("load tls credentials", zap.Error(err))
+	}
+
+	// build outbound interceptor chain
+	clientConfig, err := interceptors.NewClientConfig(envConfig, envConfig.CowboyControllerAppName)
+	if err != nil {
+		logger.Fatal("client interceptor config", zap.Error(err))
+	}
+
+	// get cowboys
+	cowboys, err := utils.GetCowboys(envConfig.CowboyListFilePath, envConfig.Replicas)
+	if err != nil {
+		logger.Fatal("get cowboys", zap.Error(err))
+	}
+
+	// init etcd, cowboys register their readiness there
+	var etcdTLSConfig *tls.Config
+	if creds.Enabled() {
+		etcdTLSConfig = creds.ClientConfig(tlsconfig.EtcdSPIFFEID)
+	}
+
+	db, err := datastore.InitEtcdDatastore(fmt.Sprintf("%s:%d", envConfig.EtcdAppName, envConfig.EtcdPort), etcdTLSConfig)
+	if err != nil {
+		logger.Fatal("init datastore", zap.Error(err))
+	}
+	defer db.Close() //nolint:errcheck
+
+	// init peer discovery
+	peerResolver, err := resolver.New(envConfig, db, net.DefaultResolver.LookupSRV, utils.ShotTransportGRPC, envConfig.GRPCPort)
+	if err != nil {
+		logger.Fatal("peer resolver", zap.Error(err))
+	}
+
+	// start grpc server with health checking and reflection, waiting until the shootout time is broadcast
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", envConfig.GRPCPort))
+	if err != nil {
+		logger.Fatal("grpc server listen", zap.Error(err))
+	}
+
+	// init metrics and server interceptors, the admin service has no validated or sheddable methods
+	metricsRegistry := metrics.New()
+	http.Handle("/metrics", metricsRegistry)
+
+	serverInterceptors := interceptors.NewServerChain(logger, interceptors.NewServerConfig(envConfig, nil, nil, metricsRegistry))
+
+	grpcServer := grpc.NewServer(creds.ServerOption(), grpc.ChainUnaryInterceptor(serverInterceptors...))
+
+	healthReporter := healthcheck.New(nil)
+	healthpb.RegisterHealthServer(grpcServer, healthReporter.Server())
+
+	// inspect and steer the game, e.g. start or pause it, and intervene in it as the game master
+	startRequest := broadcastdispatcher.NewStartRequest()
+	gameMaster := intervention.New(db, cowboys, events.New(logger, nil))
+	adminHandler := adminhandler.NewGRPC(logger, db, cowboys, startRequest, gameMaster)
+	adminpb.RegisterAdminServiceServer(grpcServer, adminHandler)
+
+	reflection.Register(grpcServer)
+
+	go func(grpcServer *grpc.Server) {
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Fatal("serve grpc server", zap.Error(err))
+		}
+	}(grpcServer)
+
+	// start readiness server, serving the result of the game too
+	http.Handle("/result", winner.Handler(logger, db))
 	go utils.StartReadinessServer(logger, envConfig.ReadinessPort)
 
-	broadcastdispatcher.BroadcastShootoutTime(logger,
-		envConfig.Replicas,
-		envConfig.CowboyAppName,
-		envConfig.CowboyAppName,
-		envConfig.GRPCPort)
+	// log the game phase changes, including those made by cowboys
+	go gamephase.NewWatcher(logger, db, envConfig.GamePhasePollInterval, nil).Run(ctx)
 
-	select {}
+	// init the first game, scheduled at the start time or the next time on the schedule, unless we restarted after
+	// doing so
+	scheduledAt := envConfig.StartAt
+	if scheduledAt.IsZero() {
+		scheduledAt = gameSchedule.Next(time.Now())
+	}
+
+	game, err := schedule.Init(ctx, db, schedule.NewGame(envConfig.GameID, 0, scheduledAt))
+	if err != nil {
+		logger.Fatal("init game", zap.Error(err))
+	}
+
+	clientConfig.GameID.Set(game.ID)
+
+	// play the rounds of the series, broadcasting the shootout time of every round unless it began before we restarted
+	seriesConfig := &series.Config{
+		DB:     db,
+		Roster: cowboys,
+		Rounds: envConfig.SeriesRounds,
+		Points: series.Points{
+			Placement: envConfig.SeriesPlacementPoints,
+			Kill:      envConfig.SeriesKillPoints,
+		},
+		RoundInterval: envConfig.SeriesRoundInterval,
+		PollInterval:  envConfig.GamePhasePollInterval,
+		Broadcast: func(ctx context.Context, round int) error {
+			cfg := &broadcastdispatcher.Config{
+				Replicas:               envConfig.Replicas,
+				DB:                     db,
+				ReadinessTimeout:       envConfig.ReadinessTimeout,
+				ReadinessTimeoutPolicy: envConfig.ReadinessTimeoutPolicy,
+				StartMode:              envConfig.StartMode,
+				Countdown:              envConfig.Countdown,
+				ClockSyncSamples:       envConfig.ClockSyncSamples,
+				Quorum:                 envConfig.StartQuorum,
+				Resolver:               peerResolver,
+				Creds:                  creds,
+				Interceptors:           interceptors.NewClientChain(logger, clientConfig),
+			}
+
+			// only the first round is scheduled and waits for the admin to start it
+			if round == 1 {
+				cfg.StartAt = game.ScheduledAt
+				cfg.ManualStart = !envConfig.AutoStart
+				cfg.StartRequest = startRequest
+			}
+
+			return broadcastdispatcher.BroadcastShootoutTime(ctx, logger.With(zap.String("game_id", game.ID)), cfg)
+		},
+		OnRoundStart: func(int) {
+			healthReporter.SetPhase(healthcheck.PhaseShooting)
+		},
+	}
+
+	for ctx.Err() == nil {
+		gameLogger := logger.With(zap.String("game_id", game.ID))
+
+		result, err := series.Run(ctx, gameLogger, seriesConfig)
+		switch {
+		case errors.Is(err, context.Canceled):
+			gameLogger.Info("series interrupted")
+			continue
+		case err != nil:
+			gameLogger.Fatal("play series", zap.Error(err))
+		}
+
+		reportResult(gameLogger, result)
+
+		if err := schedule.StoreResult(ctx, db, game, result); err != nil {
+			gameLogger.Fatal("store game result", zap.Error(err))
+		}
+
+		// play the next game on the schedule, if any
+		next := gameSchedule.Next(time.Now())
+		if next.IsZero() {
+			break
+		}
+
+		// every game waits for its own start request, set before the next game begins registering the cowboys
+		startRequest = broadcastdispatcher.NewStartRequest()
+		adminHandler.SetStartRequest(startRequest)
+
+		game, err = nextGame(ctx, db, cowboys, game, envConfig, next)
+		switch {
+		case errors.Is(err, context.Canceled):
+		case err != nil:
+			gameLogger.Fatal("begin next game", zap.Error(err))
+		default:
+			clientConfig.GameID.Set(game.ID)
+			logger.Info("next game scheduled", zap.String("game_id", game.ID), zap.Time("scheduled_at", game.ScheduledAt))
+		}
+	}
+
+	<-ctx.Done()
+	logger.Info("shutting down")
+
+	healthReporter.Shutdown()
+
+	if !utils.StopGRPCServer(grpcServer, envConfig.ShutdownTimeout) {
+		logger.Warn("grpc server stopped before pending calls finished")
+	}
+}
+
+// nextGame resets the game that's over for the next game scheduled at the given time, after a break as long as the one
+// between rounds, so that the cowboys learn how the last game ended
+func nextGame(ctx context.Context, db datastore.Datastore, roster []utils.Cowboy, last schedule.Game, envConfig utils.Environment, scheduledAt time.Time) (schedule.Game, error) {
+	select {
+	case <-ctx.Done():
+		return last, ctx.Err()
+	case <-time.After(envConfig.SeriesRoundInterval):
+	}
+
+	phase, err := gamephase.Get(ctx, db)
+	if err != nil {
+		return last, err
+	}
+
+	game := schedule.NewGame(envConfig.GameID, last.Number, scheduledAt)
+	if err := schedule.Next(ctx, db, phase, roster, game); err != nil {
+		return last, err
+	}
+
+	return game, nil
+}
+
+// reportResult logs the winner and the standings of a series
+func reportResult(logger *zap.Logger, result series.Result) {
+	if result.Aborted {
+		logger.Info("series aborted", zap.Int("rounds", result.Rounds))
+	} else {
+		logger.Info("series won", zap.Int("winner", result.Winner), zap.Int("rounds", result.Rounds))
+	}
+
+	for place, standing := range result.Standings {
+		logger.Info("standing",
+			zap.Int("place", place+1),
+			zap.Int("id", standing.ID),
+			zap.String("name", standing.Name),
+			zap.Int("points", standing.Points),
+			zap.Int("wins", standing.Wins),
+			zap.Int("kills", standing.Kills))
+	}
 }
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"wildwest/internal/tlsconfig"
+)
+
+const certgenCommand = "certgen"
+
+// runCertgen generates a local CA and certificates for every cowboy, the controller and etcd
+func runCertgen(args []string) error {
+	flags := flag.NewFlagSet(certgenCommand, flag.ContinueOnError)
+
+	outDir := flags.String("out", "certs", "output directory")
+	replicas := flags.Int("replicas", 10, "cowboy replica count")
+	cowboyAppName := flags.String("cowboy-app-name", "cowboy", "cowboy StatefulSet and service name")
+	controllerAppName := flags.String("controller-app-name", "cowboy-controller", "controller name")
+	etcdAppName := flags.String("etcd-app-name", "etcd", "etcd service name")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return tlsconfig.GenerateAll(*outDir, *replicas, *cowboyAppName, *controllerAppName, *etcdAppName)
+}
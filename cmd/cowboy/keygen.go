@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"wildwest/internal/shotauth"
+)
+
+const keygenCommand = "keygen"
+
+// runKeygen generates the shot signing keys of every cowboy
+func runKeygen(args []string) error {
+	flags := flag.NewFlagSet(keygenCommand, flag.ContinueOnError)
+
+	outDir := flags.String("out", "shot-keys", "output directory")
+	replicas := flags.Int("replicas", 10, "cowboy replica count")
+	cowboyAppName := flags.String("cowboy-app-name", "cowboy", "cowboy StatefulSet and service name")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return shotauth.GenerateAll(*outDir, *replicas, *cowboyAppName)
+}
@@ -2,37 +2,76 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
 	"time"
 	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
 	shootoutpb "wildwest/api/proto/shootout"
+	"wildwest/internal/barrier"
 	"wildwest/internal/damageapplier"
 	"wildwest/internal/datastore"
+	"wildwest/internal/events"
+	"wildwest/internal/gamephase"
 	"wildwest/internal/handlers/damagehandler"
 	"wildwest/internal/handlers/shootouthandler"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/httpgateway"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/intervention"
+	"wildwest/internal/metrics"
+	"wildwest/internal/resolver"
+	"wildwest/internal/schedule"
+	"wildwest/internal/series"
+	"wildwest/internal/shotauth"
 	"wildwest/internal/shotqueue"
 	"wildwest/internal/targetprovider"
+	"wildwest/internal/tlsconfig"
 
 	"github.com/caarlos0/env/v6"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	"wildwest/internal/shootoutstarter"
 	"wildwest/internal/shotdispatcher"
 	"wildwest/internal/shotlooper"
 	"wildwest/internal/utils"
+	"wildwest/internal/winner"
 
 	"go.uber.org/zap"
 )
 
-// TODO graceful shutdown
-
 func main() {
 	logger := utils.InitLogger()
 	defer logger.Sync() //nolint:errcheck
 
+	// run subcommand if given
+	if len(os.Args) > 1 && os.Args[1] == certgenCommand {
+		if err := runCertgen(os.Args[2:]); err != nil {
+			logger.Fatal("generate certificates", zap.Error(err))
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == keygenCommand {
+		if err := runKeygen(os.Args[2:]); err != nil {
+			logger.Fatal("generate shot keys", zap.Error(err))
+		}
+
+		return
+	}
+
 	// parse environment variables
 	var envConfig utils.Environment
 	err := env.Parse(&envConfig)
@@ -40,18 +79,68 @@ func main() {
 		logger.Fatal("parse environment", zap.Error(err))
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// check whether to forfeit or resume after being shut down mid-game
+	switch envConfig.ShutdownPolicy {
+	case utils.ShutdownPolicyResume, utils.ShutdownPolicyForfeit:
+	default:
+		logger.Fatal("unknown shutdown policy", zap.String("shutdown_policy", envConfig.ShutdownPolicy))
+	}
 
-	// get our id
-	id, err := utils.GetID(os.Hostname)
+	// check whether to keep serving or exit once the game is over
+	switch envConfig.GameOverPolicy {
+	case utils.GameOverPolicyStay, utils.GameOverPolicyExit:
+	default:
+		logger.Fatal("unknown game over policy", zap.String("game_over_policy", envConfig.GameOverPolicy))
+	}
+
+	// load mTLS credentials
+	creds, err := tlsconfig.Load(tlsconfig.Config{
+		Enabled:  envConfig.TLSEnabled,
+		CertFile: envConfig.TLSCertFile,
+		KeyFile:  envConfig.TLSKeyFile,
+		CAFile:   envConfig.TLSCAFile,
+	})
 	if err != nil {
-		logger.Fatal("get id", zap.Error(err))
+		logger.Fatal("load tls credentials", zap.Error(err))
+	}
+
+	// stop on SIGINT or SIGTERM, e.g. during a rolling restart, our life is also ended once we're dead and renewed
+	// once the game master revives us
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	life := shootoutstarter.NewLife(shutdownCtx)
+	defer life.End()
+
+	ctx := life.Context()
+
+	// get our id
+	id := envConfig.CowboyID
+	if id < 0 {
+		id, err = utils.GetID(os.Hostname)
+		if err != nil {
+			logger.Fatal("get id", zap.Error(err))
+		}
+	}
+
+	if id < 0 || id >= envConfig.Replicas {
+		logger.Fatal("id out of range", zap.Int("id", id), zap.Int("replicas", envConfig.Replicas))
 	}
 
 	// add id to logger fields
 	logger = logger.With(zap.Int("id", id))
 
+	// load shot signing keys
+	shotSigner, shotVerifier, err := shotauth.Load(shotauth.Config{
+		Enabled:        envConfig.ShotSigningEnabled,
+		KeyFile:        envConfig.ShotKeyFile,
+		PublicKeysFile: envConfig.ShotPublicKeysFile,
+		MaxAge:         envConfig.ShotMaxAge,
+	}, id)
+	if err != nil {
+		logger.Fatal("load shot keys", zap.Error(err))
+	}
+
 	// get cowboys
 	cowboys, err := utils.GetCowboys(envConfig.CowboyListFilePath, envConfig.Replicas)
 	if err != nil {
@@ -65,58 +154,423 @@ func main() {
 	logger = logger.With(zap.String("name", cowboy.Name))
 
 	// init etcd
-	db, err := datastore.InitEtcdDatastore(fmt.Sprintf("%s:%d", envConfig.EtcdAppName, envConfig.EtcdPort))
+	var etcdTLSConfig *tls.Config
+	if creds.Enabled() {
+		etcdTLSConfig = creds.ClientConfig(tlsconfig.EtcdSPIFFEID)
+	}
+
+	db, err := datastore.InitEtcdDatastore(fmt.Sprintf("%s:%d", envConfig.EtcdAppName, envConfig.EtcdPort), etcdTLSConfig)
 	if err != nil {
 		logger.Fatal("init datastore", zap.Error(err))
 	}
 	defer db.Close() //nolint:errcheck
 
+	// register our addresses for peer discovery
+	if envConfig.PeerDiscovery == utils.PeerDiscoveryRegistry {
+		if err := registerAddresses(ctx, envConfig, db, id); err != nil {
+			logger.Fatal("register addresses", zap.Error(err))
+		}
+	}
+
 	// listen on grpc port
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", envConfig.GRPCPort))
 	if err != nil {
 		logger.Fatal("grpc server listen", zap.Error(err))
 	}
 
-	// init damage applier
-	damageApplier := damageapplier.New(logger, id, db, cancel)
+	// init metrics
+	metricsRegistry := metrics.New()
+	http.Handle("/metrics", metricsRegistry)
+
+	// serve the result of the game next to the metrics
+	http.Handle("/result", winner.Handler(logger, db))
+
+	// watch the game phase, we only shoot and take damage while the game is running
+	gamePhase := gamephase.NewWatcher(logger, db, envConfig.GamePhasePollInterval, metricsRegistry)
+	go gamePhase.Run(shutdownCtx)
+
+	// init damage applier, coalescing shots if a window is set and validating them against the roster and fire rate
+	// unless anti-cheat is disabled
+	var damageApplier damageapplier.DamageApplier = damageapplier.New(logger, id, db, life.End)
+	if envConfig.DamageCoalescingWindow > 0 {
+		damageApplier = damageapplier.NewCoalescing(logger, id, db, envConfig.DamageCoalescingWindow, envConfig.DamageCoalescingMaxBatch, life.End)
+	}
+
+	if envConfig.AntiCheatEnabled {
+		damageApplier = damageapplier.NewValidating(damageApplier,
+			cowboys,
+			time.Duration(envConfig.ShotFreqMs)*time.Millisecond,
+			envConfig.AntiCheatBurst,
+			events.New(logger, metricsRegistry))
+	}
 
-	// init shootout manager
-	shootoutManager := shootoutstarter.New()
+	damageApplier = damageapplier.NewPhaseGated(damageApplier, gamePhase)
+
+	// init shootout manager, pushed the start time by the controller or pulling it from the datastore
+	var shootoutManager shootoutstarter.ShootoutStarter
+	switch envConfig.StartMode {
+	case utils.StartModePush:
+		shootoutManager = shootoutstarter.New(logger)
+	case utils.StartModePull:
+		shootoutManager = shootoutstarter.NewDatastore(logger, id, db, envConfig.StartPollInterval)
+	default:
+		logger.Fatal("unknown start mode", zap.String("start_mode", envConfig.StartMode))
+	}
+
+	// init server interceptors
+	validators := damagehandler.Validators(envConfig.Replicas)
+	for method, validator := range shootouthandler.Validators() {
+		validators[method] = validator
+	}
+
+	serverInterceptors := interceptors.NewServerChain(logger, interceptors.NewServerConfig(envConfig, validators, damagehandler.SheddableMethods(), metricsRegistry))
 
 	// init grpc servers
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(creds.ServerOption(), grpc.ChainUnaryInterceptor(serverInterceptors...))
 
-	damageHandler := damagehandler.NewGRPC(logger, damageApplier)
+	damageHandler := damagehandler.NewGRPC(logger, damageApplier, shotVerifier, serverInterceptors)
 	damagepb.RegisterDamageServiceServer(grpcServer, damageHandler)
 
+	damageV2Handler := damagehandler.NewGRPCV2(logger, damageApplier, shotVerifier, serverInterceptors)
+	damagev2pb.RegisterDamageServiceServer(grpcServer, damageV2Handler)
+
 	shootoutHandler := shootouthandler.NewGRPC(shootoutManager)
 	shootoutpb.RegisterShootoutServiceServer(grpcServer, shootoutHandler)
 
-	// start grpc server
+	// report the game phase with grpc.health.v1, the damage services serve until we're dead or the winner
+	healthReporter := healthcheck.New(map[string][]healthcheck.Phase{
+		damagepb.DamageService_ServiceDesc.ServiceName:     {healthcheck.PhaseWaiting, healthcheck.PhaseShooting},
+		damagev2pb.DamageService_ServiceDesc.ServiceName:   {healthcheck.PhaseWaiting, healthcheck.PhaseShooting},
+		shootoutpb.ShootoutService_ServiceDesc.ServiceName: {healthcheck.PhaseWaiting},
+	})
+	healthpb.RegisterHealthServer(grpcServer, healthReporter.Server())
+
+	reflection.Register(grpcServer)
+
+	// start grpc server, it returns nil once stopped
 	go func(grpcServer *grpc.Server) {
 		if err := grpcServer.Serve(lis); err != nil {
 			logger.Fatal("serve grpc server", zap.Error(err))
 		}
 	}(grpcServer)
 
+	// start http/json gateway next to the grpc server
+	var httpServer *http.Server
+	if envConfig.HTTPPort != 0 {
+		mux := http.NewServeMux()
+		httpgateway.RegisterDamageService(mux, damageHandler, serverInterceptors)
+		httpgateway.RegisterDamageV2Service(mux, damageV2Handler, serverInterceptors)
+		httpgateway.RegisterShootoutService(mux, shootoutHandler, serverInterceptors)
+
+		httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", envConfig.HTTPPort),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func(httpServer *http.Server) {
+			var err error
+			if creds.Enabled() {
+				httpServer.TLSConfig = creds.ServerConfig()
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
+			}
+
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal("serve http gateway", zap.Error(err))
+			}
+		}(httpServer)
+	}
+
 	shotQueue := shotqueue.New(time.Duration(envConfig.ShotFreqMs) * time.Millisecond)
-	shotDispatcher := shotdispatcher.NewGRPC(logger, envConfig.CowboyAppName, envConfig.CowboyAppName, envConfig.GRPCPort)
-	targetProvider := targetprovider.New(id, db)
 
-	shooterHandler := shotlooper.New(logger, id, cowboy, db, shotQueue, shotDispatcher, targetProvider)
+	// build outbound interceptor chain
+	clientConfig, err := interceptors.NewClientConfig(envConfig, fmt.Sprintf("%s-%d", envConfig.CowboyAppName, id))
+	if err != nil {
+		logger.Fatal("client interceptor config", zap.Error(err))
+	}
+
+	clientInterceptors := interceptors.NewClientChain(logger, clientConfig)
+
+	// select shot transport
+	var shotDispatcher shotdispatcher.ShotDispatcher
+	switch envConfig.ShotTransport {
+	case utils.ShotTransportGRPC:
+		peerResolver, err := resolver.New(envConfig, db, net.DefaultResolver.LookupSRV, utils.ShotTransportGRPC, envConfig.GRPCPort)
+		if err != nil {
+			logger.Fatal("peer resolver", zap.Error(err))
+		}
+
+		shotDispatcher = shotdispatcher.NewGRPC(logger, peerResolver, creds, shotSigner, clientInterceptors, envConfig.DamageStreamWindow)
+	case utils.ShotTransportHTTP:
+		peerResolver, err := resolver.New(envConfig, db, net.DefaultResolver.LookupSRV, utils.ShotTransportHTTP, envConfig.HTTPPort)
+		if err != nil {
+			logger.Fatal("peer resolver", zap.Error(err))
+		}
+
+		shotDispatcher = shotdispatcher.NewHTTP(logger, peerResolver, creds, shotSigner, clientInterceptors)
+	default:
+		logger.Fatal("unknown shot transport", zap.String("shot_transport", envConfig.ShotTransport))
+	}
 
-	isWinner := shootoutstarter.Start(ctx, logger, &shootoutstarter.Config{
+	targetProvider := targetprovider.New(id, db, envConfig.TargetRefreshInterval)
+
+	// refresh our view of the cowboys' health once the game master changed it
+	go intervention.NewWatcher(logger, db, envConfig.GamePhasePollInterval, targetProvider.Invalidate).Run(shutdownCtx)
+
+	shooterHandler := shotlooper.New(logger, id, cowboy, db, shotQueue, shotDispatcher, targetProvider, gamePhase)
+
+	// play the shootout in the background, so that we can be shut down at any point of it
+	result := make(chan bool, 1)
+
+	// we're ready again for every round, but serve readiness once
+	readyOnce := &atomic.Bool{}
+
+	shootoutConfig := &shootoutstarter.Config{
 		ID:              id,
 		Cowboy:          cowboy,
 		DB:              db,
 		ShooterHandler:  shooterHandler,
 		ShootoutManager: shootoutManager,
-		Ready:           func() { utils.StartReadinessServer(logger, envConfig.ReadinessPort) },
-	})
+		StartMode:       envConfig.StartMode,
+		Replicas:        envConfig.Replicas,
+		Ready: func() {
+			// our health is initialized and our servers are serving, let the controller know
+			if err := barrier.Register(shutdownCtx, db, id); err != nil {
+				logger.Fatal("register readiness", zap.Error(err))
+			}
+
+			if readyOnce.CompareAndSwap(false, true) {
+				utils.StartReadinessServer(logger, envConfig.ReadinessPort)
+			}
+		},
+		Health: healthReporter,
+	}
 
-	if isWinner {
-		logger.Info("i am the winner!")
+	// resume the game and the round of its series we restarted in
+	game, err := schedule.Current(ctx, db)
+	if err != nil && !errors.Is(err, schedule.ErrNoGame) {
+		logger.Fatal("get game", zap.Error(err))
+	}
+
+	clientConfig.GameID.Set(game.ID)
+
+	round, err := series.CurrentRound(ctx, db)
+	if err != nil {
+		logger.Fatal("get round", zap.Error(err))
+	}
+
+	// play the scheduled games one after the other unless we exit once a game is over
+	recurring := envConfig.Schedule != "" && envConfig.GameOverPolicy == utils.GameOverPolicyStay
+
+	waitForGame := func() bool {
+		// we may have started before the controller stored the game we played
+		if game.Number == 0 {
+			if current, err := schedule.Current(shutdownCtx, db); err == nil {
+				game = current
+				clientConfig.GameID.Set(game.ID)
+			}
+		}
+
+		gameOver(logger, envConfig, db, id)
+
+		_, ok := schedule.WaitForGame(shutdownCtx, logger, db, game.Number, envConfig.GamePhasePollInterval)
+
+		return ok
+	}
+
+	go func() {
+		for {
+			isWinner := shootoutstarter.Start(life.Context(), logger, shootoutConfig)
+
+			switch {
+			case !isWinner && intervention.WaitForRevival(shutdownCtx, logger, db, id, envConfig.GamePhasePollInterval):
+			case round < envConfig.SeriesRounds && series.WaitForRound(shutdownCtx, logger, db, round+1, envConfig.GamePhasePollInterval):
+			case recurring && waitForGame():
+			default:
+				result <- isWinner
+				return
+			}
+
+			// the next game or round resets our health and phase like a revival does
+			currentGame, gameErr := schedule.Current(shutdownCtx, db)
+			currentRound, roundErr := series.CurrentRound(shutdownCtx, db)
+
+			switch {
+			case gameErr == nil && roundErr == nil && currentGame.Number > game.Number:
+				game, round = currentGame, currentRound
+				clientConfig.GameID.Set(game.ID)
+				logger.Info("next game begins", zap.String("game_id", game.ID))
+			case roundErr == nil && currentRound > round:
+				round = currentRound
+				logger.Info("next round begins", zap.Int("round", round))
+			default:
+				logger.Info("revived by the game master")
+			}
+
+			life.Renew()
+			targetProvider.Invalidate()
+		}
+	}()
+
+	forfeit := false
+	exitCode := 0
+
+	select {
+	case <-result:
+		shotQueue.Stop()
+
+		outcomeExitCode, over := gameOver(logger, envConfig, db, id)
+		switch {
+		case !over:
+			// we stopped playing because we're shut down
+			logger.Info("shutting down mid-game", zap.String("shutdown_policy", envConfig.ShutdownPolicy))
+			forfeit = envConfig.ShutdownPolicy == utils.ShutdownPolicyForfeit
+		case envConfig.GameOverPolicy == utils.GameOverPolicyExit:
+			exitCode = outcomeExitCode
+			logger.Info("exiting", zap.Int("exit_code", exitCode))
+		default:
+			// keep serving the result until we're shut down
+			<-shutdownCtx.Done()
+			logger.Info("shutting down")
+		}
+	case <-shutdownCtx.Done():
+		logger.Info("shutting down mid-game", zap.String("shutdown_policy", envConfig.ShutdownPolicy))
+
+		// the shot loop stops with ctx, waiting for the shootout time doesn't
+		shotQueue.Stop()
+
+		select {
+		case <-result:
+		case <-time.After(envConfig.ShutdownTimeout):
+			logger.Warn("shootout didn't stop in time")
+		}
+
+		forfeit = envConfig.ShutdownPolicy == utils.ShutdownPolicyForfeit
+	}
+
+	// stop accepting calls and drain the pending damage calls
+	healthReporter.Shutdown()
+
+	if !utils.StopGRPCServer(grpcServer, envConfig.ShutdownTimeout) {
+		logger.Warn("grpc server stopped before pending calls finished")
+	}
+
+	if httpServer != nil {
+		httpCtx, httpCtxCancel := context.WithTimeout(context.Background(), envConfig.ShutdownTimeout)
+		defer httpCtxCancel()
+
+		if err := httpServer.Shutdown(httpCtx); err != nil {
+			logger.Warn("shut down http gateway", zap.Error(err))
+		}
+	}
+
+	// leave the game for good, otherwise our phase and health are kept to resume after restarting
+	if forfeit {
+		forfeitCtx, forfeitCtxCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer forfeitCtxCancel()
+
+		if err := shootoutstarter.Forfeit(forfeitCtx, db, id); err != nil {
+			logger.Error("forfeit", zap.Error(err))
+		} else {
+			logger.Info("forfeited")
+		}
+	}
+
+	// deferred calls don't run on exit
+	if exitCode != 0 {
+		if err := db.Close(); err != nil {
+			logger.Warn("close datastore", zap.Error(err))
+		}
+
+		_ = logger.Sync()
+
+		os.Exit(exitCode)
+	}
+}
+
+// gameOver logs the result of the game once it's over and returns the exit code of our outcome, over is false if the
+// game isn't over, e.g. because we stopped playing when we were shut down
+func gameOver(logger *zap.Logger, envConfig utils.Environment, db datastore.Datastore, id int) (exitCode int, over bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if envConfig.SeriesRounds > 1 {
+		return seriesOver(ctx, logger, envConfig, db, id)
+	}
+
+	result, err := winner.GetResult(ctx, db)
+	if err != nil {
+		logger.Error("get game result", zap.Error(err))
+		return 0, false
+	}
+
+	switch {
+	case !result.GamePhase.Terminal():
+		return 0, false
+	case result.GamePhase == gamephase.Aborted:
+		logger.Info("game aborted")
+		return envConfig.AbortedExitCode, true
+	case result.Winner == nil:
+		logger.Warn("game finished without a winner record")
+		return envConfig.LoserExitCode, true
+	case result.Winner.ID == id:
+		logger.Info("i am the winner!", zap.Int("health", result.Winner.Health))
+		return envConfig.WinnerExitCode, true
+	default:
+		logger.Info("game won by another cowboy", zap.Int("winner", result.Winner.ID), zap.String("winner_name", result.Winner.Name))
+		return envConfig.LoserExitCode, true
+	}
+}
+
+// seriesOver logs the result of the series once it's over and returns the exit code of our outcome, over is false if
+// the series isn't over
+func seriesOver(ctx context.Context, logger *zap.Logger, envConfig utils.Environment, db datastore.Datastore, id int) (exitCode int, over bool) {
+	result, err := series.GetResult(ctx, db)
+	if errors.Is(err, series.ErrNoResult) {
+		return 0, false
+	}
+
+	if err != nil {
+		logger.Error("get series result", zap.Error(err))
+		return 0, false
+	}
+
+	switch {
+	case result.Aborted:
+		logger.Info("series aborted", zap.Int("rounds", result.Rounds))
+		return envConfig.AbortedExitCode, true
+	case result.Winner == id:
+		logger.Info("i won the series!", zap.Int("rounds", result.Rounds))
+		return envConfig.WinnerExitCode, true
+	default:
+		logger.Info("series won by another cowboy", zap.Int("winner", result.Winner), zap.Int("rounds", result.Rounds))
+		return envConfig.LoserExitCode, true
+	}
+}
+
+// registerAddresses stores the addresses of our grpc server and http gateway in the datastore
+func registerAddresses(ctx context.Context, envConfig utils.Environment, db datastore.Datastore, id int) error {
+	host := envConfig.AdvertiseHost
+	if host == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("get hostname: %w", err)
+		}
+
+		host = hostname
+	}
+
+	if err := resolver.Register(ctx, db, utils.ShotTransportGRPC, id, net.JoinHostPort(host, strconv.Itoa(envConfig.GRPCPort))); err != nil {
+		return err
+	}
+
+	if envConfig.HTTPPort != 0 {
+		if err := resolver.Register(ctx, db, utils.ShotTransportHTTP, id, net.JoinHostPort(host, strconv.Itoa(envConfig.HTTPPort))); err != nil {
+			return err
+		}
 	}
 
-	select {}
+	return nil
 }
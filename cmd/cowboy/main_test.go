@@ -9,6 +9,7 @@ import (
 	"time"
 	"wildwest/internal/damageapplier"
 	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
 	"wildwest/internal/shootoutstarter"
 	"wildwest/internal/shotqueue"
 	"wildwest/internal/targetprovider"
@@ -37,8 +38,9 @@ func TestMainFunc(t *testing.T) {
 	replicas := 1000
 	shotFrequencyMs := 1
 
-	// init datastore
+	// init datastore, the game is running as if started by the controller
 	db := datastore.NewFakeClient()
+	assert.NoError(t, db.Put(context.Background(), gamephase.Key, string(gamephase.Running)))
 
 	damageAppliers := make([]damageapplier.DamageApplier, replicas)
 
@@ -87,14 +89,14 @@ func TestMainFunc(t *testing.T) {
 
 			shotQueue := shotqueue.New(time.Duration(shotFrequencyMs) * time.Millisecond)
 			shotDispatcher := shotdispatcher.NewFake(logger, damageAppliers)
-			targetProvider := targetprovider.New(id, db)
+			targetProvider := targetprovider.New(id, db, 0)
 
-			shooterHandler := shotlooper.New(logger, id, cowboy, db, shotQueue, shotDispatcher, targetProvider)
+			shooterHandler := shotlooper.New(logger, id, cowboy, db, shotQueue, shotDispatcher, targetProvider, nil)
 
-			shootoutManager := shootoutstarter.New()
+			shootoutManager := shootoutstarter.New(logger)
 
 			// mock call to begin shootout
-			shootoutManager.ReceiveShootoutTime(shootoutBeginTime)
+			shootoutManager.ReceiveShootoutTime(shootoutBeginTime, 0)
 
 			isWinner := shootoutstarter.Start(ctx, logger, &shootoutstarter.Config{
 				ID:              id,
@@ -102,6 +104,7 @@ func TestMainFunc(t *testing.T) {
 				DB:              db,
 				ShooterHandler:  shooterHandler,
 				ShootoutManager: shootoutManager,
+				Replicas:        replicas,
 				Ready:           func() {},
 			})
 
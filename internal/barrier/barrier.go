@@ -0,0 +1,88 @@
+package barrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/utils"
+)
+
+const ErrTimeout = utils.ConstError("readiness barrier timed out")
+
+// ReadyKeyPrefix prefixes the keys marking the cowboys as ready
+const ReadyKeyPrefix = "ready-"
+
+// ReadyKey returns the datastore key marking a cowboy as ready, e.g. ready-1
+func ReadyKey(id int) string {
+	return ReadyKeyPrefix + strconv.Itoa(id)
+}
+
+// Register marks our cowboy as ready, to be called once its health is initialized and its servers are serving
+func Register(ctx context.Context, db datastore.Datastore, id int) error {
+	if err := db.Put(ctx, ReadyKey(id), time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		return fmt.Errorf("register readiness: %w", err)
+	}
+
+	return nil
+}
+
+// Wait polls the datastore every pollInterval until all replicas are ready and returns their ids.
+// If ctx is done first it returns the ids of the ready cowboys with ErrTimeout
+func Wait(ctx context.Context, db datastore.Datastore, replicas int, pollInterval time.Duration) ([]int, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var ready []int
+
+	for {
+		ids, err := Ready(ctx, db, replicas)
+		switch {
+		case err == nil:
+			ready = ids
+		case ctx.Err() == nil:
+			return nil, err
+		}
+
+		if len(ready) == replicas {
+			return ready, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ready, fmt.Errorf("%w: %d of %d cowboys ready", ErrTimeout, len(ready), replicas)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Ready returns the sorted ids of the ready cowboys, ignoring ids beyond replicas
+func Ready(ctx context.Context, db datastore.Datastore, replicas int) ([]int, error) {
+	kvs, err := db.GetPrefix(ctx, ReadyKeyPrefix)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get ready cowboys: %w", err)
+	}
+
+	ids := make([]int, 0, len(kvs))
+
+	for key := range kvs {
+		id, err := strconv.Atoi(strings.TrimPrefix(key, ReadyKeyPrefix))
+		if err != nil || id < 0 || id >= replicas {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,64 @@
+package barrier_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/barrier"
+	"wildwest/internal/datastore"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	tests := []struct {
+		name      string
+		replicas  int
+		readyIDs  []int
+		lateIDs   []int
+		wantReady []int
+		wantErr   error
+	}{
+		{"all ready", 3, []int{0, 1, 2}, nil, []int{0, 1, 2}, nil},
+		{"ready while waiting", 3, []int{0}, []int{2, 1}, []int{0, 1, 2}, nil},
+		{"timeout with ready subset", 3, []int{0, 2}, nil, []int{0, 2}, barrier.ErrTimeout},
+		{"timeout with none ready", 2, nil, nil, nil, barrier.ErrTimeout},
+		{"ids beyond replicas are ignored", 2, []int{0, 1, 5}, nil, []int{0, 1}, nil},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			fakeDatastore := datastore.NewFakeClient()
+
+			for _, id := range tc.readyIDs {
+				assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+			}
+
+			// the late cowboys register before the subtest returns
+			registered := make(chan struct{})
+			defer func() { <-registered }()
+
+			go func() {
+				defer close(registered)
+
+				for _, id := range tc.lateIDs {
+					time.Sleep(10 * time.Millisecond)
+					assert.NoError(t, barrier.Register(context.Background(), fakeDatastore, id))
+				}
+			}()
+
+			// execute
+			ready, err := barrier.Wait(ctx, fakeDatastore, tc.replicas, time.Millisecond)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantReady, ready)
+		})
+	}
+}
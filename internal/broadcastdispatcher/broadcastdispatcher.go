@@ -2,83 +2,559 @@ package broadcastdispatcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"net"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"sync"
 	"time"
 	shootoutpb "wildwest/api/proto/shootout"
+	"wildwest/internal/barrier"
+	"wildwest/internal/clocksync"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/resolver"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/tlsconfig"
+	"wildwest/internal/utils"
 )
 
-// BroadcastShootoutTime waits until all cowboys are ready and broadcasts to them when to begin the shootout
-func BroadcastShootoutTime(logger *zap.Logger, replicas int, podName string, serviceName string, grpcPort int) {
-	// wait until all replicas are ready by looking up hostname
+const (
+	ErrQuorumNotReached = utils.ConstError("start quorum not reached")
+	ErrNoCowboysReady   = utils.ConstError("no cowboys ready")
+)
+
+// readinessPollInterval is how often the readiness barrier is checked
+const readinessPollInterval = 500 * time.Millisecond
+
+const (
+	initialDeliveryBackoff = 100 * time.Millisecond
+	maxDeliveryBackoff     = 2 * time.Second
+)
+
+// confirmationMargin is how long before the shootout the start time deliveries end, so that the start is confirmed
+// and the game is running by the time the cowboys begin shooting
+const confirmationMargin = time.Second
+
+type Config struct {
+	Replicas int
+	DB       datastore.Datastore
+
+	// ReadinessTimeout bounds the wait for all cowboys to be ready, ReadinessTimeoutPolicy decides whether the
+	// shootout then starts with the ready cowboys or is aborted
+	ReadinessTimeout       time.Duration
+	ReadinessTimeoutPolicy string
+	// ManualStart waits for StartRequest instead of the cowboys getting ready, otherwise StartRequest cuts the wait
+	// short, optional
+	ManualStart  bool
+	StartRequest *StartRequest
+
+	// StartMode selects whether the shootout time is pushed to the cowboys or published in the datastore
+	StartMode string
+	// Countdown is the time between the broadcast and the shootout, in which the start time is delivered
+	Countdown time.Duration
+	// StartAt schedules the shootout, the countdown to it begins once the cowboys are ready. Zero or a time the
+	// countdown can't make starts the shootout Countdown after the cowboys are ready, optional
+	StartAt time.Time
+	// ClockSyncSamples is how many clock offset estimation exchanges are made with every cowboy, 0 skips them. In pull
+	// mode the offsets are only estimated if Resolver is set
+	ClockSyncSamples int
+	// Quorum is how many cowboys must acknowledge the start time for the start to be confirmed, 0 means every ready cowboy
+	Quorum int
+
+	Resolver     resolver.Resolver
+	Creds        *tlsconfig.Credentials
+	Interceptors []grpc.UnaryClientInterceptor
+}
+
+// StartRequest starts the shootout with the ready cowboys once requested, e.g. by an operator
+type StartRequest struct {
+	once      *sync.Once
+	requested chan struct{}
+}
+
+func NewStartRequest() *StartRequest {
+	return &StartRequest{
+		once:      &sync.Once{},
+		requested: make(chan struct{}),
+	}
+}
+
+// Request requests the start, later requests are ignored
+func (sr *StartRequest) Request() {
+	sr.once.Do(func() {
+		close(sr.requested)
+	})
+}
+
+// Requested is closed once the start was requested, it's never closed for a nil StartRequest
+func (sr *StartRequest) Requested() <-chan struct{} {
+	if sr == nil {
+		return nil
+	}
+
+	return sr.requested
+}
+
+type deliveryState int
+
+const (
+	deliveryPending deliveryState = iota
+	deliveryAcked
+	deliveryFailed
+)
+
+func (ds deliveryState) String() string {
+	switch ds {
+	case deliveryPending:
+		return "pending"
+	case deliveryAcked:
+		return "acked"
+	case deliveryFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// delivery tracks the start time delivery to a single cowboy
+type delivery struct {
+	id       int
+	state    deliveryState
+	attempts int
+	err      error
+}
+
+// BroadcastShootoutTime waits until the cowboys are ready, and until the countdown begins if the shootout is scheduled,
+// and lets them know when to begin the shootout, storing it in the datastore and, unless they pull it from there,
+// delivering it with retries until shortly before the shootout begins. Once the quorum acknowledged the start time, the
+// cowboys which didn't acknowledge it forfeit and the game is running, the cowboys begin shooting at the start time.
+// The game moves through the Registering, Countdown and Running phases, it's aborted if the cowboys don't get ready or
+// the quorum isn't reached. Canceling ctx stops the broadcast and leaves the game in its phase
+func BroadcastShootoutTime(ctx context.Context, logger *zap.Logger, cfg *Config) error {
+	if err := gamephase.Init(ctx, cfg.DB); err != nil {
+		return err
+	}
+
+	ready, err := waitForCowboys(ctx, logger, cfg)
+	if err != nil {
+		return err
+	}
+
+	shootoutTime, err := scheduleShootout(ctx, logger, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := transition(logger, cfg.DB, gamephase.Registering, gamephase.Countdown); err != nil {
+		return err
+	}
+
+	confirmAt := confirmationTime(shootoutTime)
+
+	// cowboys pulling the shootout time read their clock offsets once it's stored
+	if cfg.StartMode == utils.StartModePull && cfg.ClockSyncSamples > 0 && cfg.Resolver != nil {
+		syncClocks(ctx, logger, cfg, ready, confirmAt)
+	}
+
+	// store the shootout time, so that cowboys pulling it or restarting before the shootout learn it
+	if err := storeShootoutTime(cfg.DB, shootoutTime); err != nil {
+		return err
+	}
+
+	var acked map[int]bool
+	if cfg.StartMode == utils.StartModePull {
+		acked, err = pullAcknowledgements(ctx, logger, cfg, shootoutTime, confirmAt)
+	} else {
+		acked = pushShootoutTime(ctx, logger, cfg, ready, shootoutTime, confirmAt)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("deliver shootout time: %w", ctx.Err())
+	}
+
+	// confirm the start if enough cowboys acknowledged it, the cowboys only take damage once the game is running
+	quorum := cfg.Quorum
+	if quorum <= 0 {
+		quorum = len(ready)
+	}
+
+	if len(acked) < quorum {
+		abort(logger, cfg.DB, gamephase.Countdown)
+		return fmt.Errorf("%w: %d of %d cowboys acknowledged", ErrQuorumNotReached, len(acked), quorum)
+	}
+
+	logger.Info("shootout start confirmed", zap.Int("acked", len(acked)), zap.Int("quorum", quorum))
+
+	// exclude the cowboys which never acknowledged the start
+	for id := 0; id < cfg.Replicas; id++ {
+		if acked[id] {
+			continue
+		}
+
+		if err := forfeit(cfg.DB, id); err != nil {
+			return err
+		}
+
+		logger.Warn("cowboy forfeited", zap.Int("peer", id))
+	}
+
+	return transition(logger, cfg.DB, gamephase.Countdown, gamephase.Running)
+}
+
+// confirmationTime returns when the start time deliveries end, confirmationMargin before the shootout or halfway to it
+// if it's sooner
+func confirmationTime(shootoutTime time.Time) time.Time {
+	margin := time.Until(shootoutTime) / 2
+	if margin > confirmationMargin {
+		margin = confirmationMargin
+	}
+
+	if margin < 0 {
+		margin = 0
+	}
+
+	return shootoutTime.Add(-margin)
+}
+
+// scheduleShootout waits until the countdown to the scheduled shootout begins and returns the shootout time. Without a
+// schedule, if the schedule can't be made or once a start is requested the shootout is Countdown from now
+func scheduleShootout(ctx context.Context, logger *zap.Logger, cfg *Config) (time.Time, error) {
+	shootoutTime := time.Now().Add(cfg.Countdown)
+	if !cfg.StartAt.After(shootoutTime) {
+		if !cfg.StartAt.IsZero() {
+			logger.Warn("missed the scheduled shootout", zap.Time("start_at", cfg.StartAt), zap.Time("shootout_time", shootoutTime))
+		}
+
+		return shootoutTime, nil
+	}
+
+	logger.Info("waiting for the scheduled shootout...", zap.Time("start_at", cfg.StartAt))
+
+	timer := time.NewTimer(time.Until(cfg.StartAt.Add(-cfg.Countdown)))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return time.Time{}, fmt.Errorf("wait for the scheduled shootout: %w", ctx.Err())
+	case <-cfg.StartRequest.Requested():
+		logger.Info("start requested before the scheduled shootout")
+		return time.Now().Add(cfg.Countdown), nil
+	case <-timer.C:
+		return cfg.StartAt, nil
+	}
+}
+
+// waitForCowboys returns the ids of the cowboys to start the shootout with. Unless the start is manual, it waits until
+// all cowboys registered their readiness or the readiness timeout passes, a start request starts the shootout with the
+// ready cowboys right away
+func waitForCowboys(ctx context.Context, logger *zap.Logger, cfg *Config) ([]int, error) {
+	if cfg.ManualStart {
+		logger.Info("waiting for a start request...")
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for start request: %w", ctx.Err())
+		case <-cfg.StartRequest.Requested():
+		}
+
+		ready, err := barrier.Ready(ctx, cfg.DB, cfg.Replicas)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ready) == 0 {
+			abort(logger, cfg.DB, gamephase.Registering)
+			return nil, ErrNoCowboysReady
+		}
+
+		logger.Info("starting shootout on request", zap.Ints("ready", ready))
+
+		return ready, nil
+	}
+
+	readyCtx, cancel := context.WithTimeout(ctx, cfg.ReadinessTimeout)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-cfg.StartRequest.Requested():
+			cancel()
+		case <-readyCtx.Done():
+		}
+	}()
+
+	ready, err := barrier.Wait(readyCtx, cfg.DB, cfg.Replicas, readinessPollInterval)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("wait for cowboys: %w", ctx.Err())
+	}
+
+	switch {
+	case err == nil:
+		return ready, nil
+	case errors.Is(err, barrier.ErrTimeout) && startRequested(cfg) && len(ready) > 0:
+		logger.Info("starting shootout on request", zap.Ints("ready", ready))
+		return ready, nil
+	case errors.Is(err, barrier.ErrTimeout) && cfg.ReadinessTimeoutPolicy == utils.ReadinessTimeoutPolicyStart && len(ready) > 0:
+		logger.Warn("starting shootout with the ready cowboys", zap.Ints("ready", ready), zap.Error(err))
+		return ready, nil
+	default:
+		abort(logger, cfg.DB, gamephase.Registering)
+		return nil, fmt.Errorf("wait for cowboys: %w", err)
+	}
+}
+
+func startRequested(cfg *Config) bool {
+	select {
+	case <-cfg.StartRequest.Requested():
+		return true
+	default:
+		return false
+	}
+}
+
+// transition moves the game to the next phase
+func transition(logger *zap.Logger, db datastore.Datastore, from, to gamephase.Phase) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := gamephase.Transition(ctx, db, from, to); err != nil {
+		return err
+	}
+
+	logger.Info("game phase changed", zap.String("from", string(from)), zap.String("to", string(to)))
+
+	return nil
+}
+
+// abort moves the game to the Aborted phase, failing to do so is only logged as the game can't go on anyway
+func abort(logger *zap.Logger, db datastore.Datastore, from gamephase.Phase) {
+	if err := transition(logger, db, from, gamephase.Aborted); err != nil {
+		logger.Error("abort game", zap.Error(err))
+	}
+}
+
+// pushShootoutTime delivers the shootout time to the ready cowboys until confirmAt and returns the ids of the cowboys
+// which acknowledged it
+func pushShootoutTime(ctx context.Context, logger *zap.Logger, cfg *Config, ready []int, shootoutTime time.Time, confirmAt time.Time) map[int]bool {
+	logger.Info("broadcasting shootout beginning time...", zap.Time("shootout_time", shootoutTime))
+
+	ctx, cancel := context.WithDeadline(ctx, confirmAt)
+	defer cancel()
+
+	deliveries := make([]*delivery, len(ready))
+
 	var wg sync.WaitGroup
-	wg.Add(replicas)
+	wg.Add(len(ready))
+
+	for i, id := range ready {
+		deliveries[i] = &delivery{id: id}
 
-	for i := 0; i < replicas; i++ {
-		go func(wg *sync.WaitGroup, id int) {
+		go func(d *delivery) {
 			defer wg.Done()
 
-			// build hostname
-			hostname := fmt.Sprintf("%s-%d.%s", podName, id, serviceName)
+			deliver(ctx, logger, cfg, d, shootoutTime)
+		}(deliveries[i])
+	}
 
-			retryCount := 0
+	wg.Wait()
 
-			// TODO exit after N retries, use exponential backoff
-			_, err := net.LookupHost(hostname)
-			for err != nil {
-				retryCount++
-				if retryCount > 20 {
-					logger.Warn("retry count above 20", zap.String("hostname", hostname))
-				}
+	acked := make(map[int]bool, len(deliveries))
+	for _, d := range deliveries {
+		logger.Info("shootout time delivery",
+			zap.Int("peer", d.id),
+			zap.Stringer("state", d.state),
+			zap.Int("attempts", d.attempts),
+			zap.NamedError("last_error", d.err),
+		)
 
-				time.Sleep(5 * time.Second)
+		if d.state == deliveryAcked {
+			acked[d.id] = true
+		}
+	}
 
-				_, err = net.LookupHost(hostname)
-			}
-		}(&wg, i)
+	return acked
+}
+
+// storeShootoutTime stores the shootout time at shootoutstarter.StartAtKey
+func storeShootoutTime(db datastore.Datastore, shootoutTime time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.Put(ctx, shootoutstarter.StartAtKey, shootoutstarter.FormatStartAt(shootoutTime)); err != nil {
+		return fmt.Errorf("store shootout time: %w", err)
 	}
-	wg.Wait()
 
-	// begin shootout in 10 seconds from now
-	shootoutTime := time.Now().Add(10 * time.Second).Round(time.Second)
-	shootoutTimestamp := shootoutTime.Unix()
+	return nil
+}
 
-	logger.Info("broadcasting shootout beginning time...", zap.Time("shootout_time", shootoutTime))
+// pullAcknowledgements waits until confirmAt while the cowboys pull the shootout time and returns the ids of the
+// cowboys which acknowledged it
+func pullAcknowledgements(ctx context.Context, logger *zap.Logger, cfg *Config, shootoutTime time.Time, confirmAt time.Time) (map[int]bool, error) {
+	logger.Info("published shootout beginning time", zap.Time("shootout_time", shootoutTime))
+
+	timer := time.NewTimer(time.Until(confirmAt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for shootout: %w", ctx.Err())
+	case <-timer.C:
+	}
+
+	ackCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return shootoutstarter.AcknowledgedIDs(ackCtx, cfg.DB, cfg.Replicas)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(replicas/5+2)*time.Second)
+// deliver sends the shootout time to a cowboy with exponential backoff until it's acknowledged or ctx is done
+func deliver(ctx context.Context, logger *zap.Logger, cfg *Config, d *delivery, shootoutTime time.Time) {
+	logger = logger.With(zap.Int("peer", d.id))
+
+	backoff := initialDeliveryBackoff
+
+	for {
+		d.attempts++
+
+		d.err = sendShootoutTime(ctx, logger, cfg, d.id, shootoutTime)
+		if d.err == nil {
+			d.state = deliveryAcked
+			logger.Debug("shootout time acknowledged", zap.Int("attempts", d.attempts))
+
+			return
+		}
+
+		logger.Warn("failed to deliver shootout time", zap.Int("attempts", d.attempts), zap.Error(d.err))
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.state = deliveryFailed
+
+			return
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > maxDeliveryBackoff {
+			backoff = maxDeliveryBackoff
+		}
+	}
+}
+
+// sendShootoutTime makes a single delivery attempt
+func sendShootoutTime(ctx context.Context, logger *zap.Logger, cfg *Config, id int, shootoutTime time.Time) error {
+	conn, err := dialCowboy(ctx, cfg, id)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := shootoutpb.NewShootoutServiceClient(conn)
+
+	var clockOffset time.Duration
+	if cfg.ClockSyncSamples > 0 {
+		clockOffset, err = estimateClockOffset(ctx, logger, cfg, client, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	// begin shootout
+	_, err = client.ReceiveShootoutTime(ctx, &shootoutpb.ReceiveShootoutTimeRequest{
+		Timestamp:   shootoutTime.Unix(),
+		StartTime:   timestamppb.New(shootoutTime),
+		ClockOffset: durationpb.New(clockOffset),
+	})
+
+	return err
+}
+
+// syncClocks estimates and stores the clock offsets of the ready cowboys until deadline, so that cowboys pulling the
+// shootout time correct it like pushed ones
+func syncClocks(ctx context.Context, logger *zap.Logger, cfg *Config, ready []int, deadline time.Time) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
-	wg.Add(replicas)
+	var wg sync.WaitGroup
+	wg.Add(len(ready))
 
-	// broadcast the shootout time
-	for i := 0; i < replicas; i++ {
-		go func(ctx context.Context, id int) {
+	for _, id := range ready {
+		go func(id int) {
 			defer wg.Done()
 
-			// build hostname
-			hostname := fmt.Sprintf("%s-%d.%s:%d", podName, id, serviceName, grpcPort)
+			logger := logger.With(zap.Int("peer", id))
 
-			// dial cowboy
-			conn, err := grpc.Dial(hostname, grpc.WithTransportCredentials(insecure.NewCredentials())) // TODO insecure
+			conn, err := dialCowboy(ctx, cfg, id)
 			if err != nil {
-				logger.Fatal("failed to dial", zap.Error(err))
+				logger.Warn("failed to sync clock", zap.Error(err))
+				return
 			}
+			defer conn.Close()
 
-			// create client
-			client := shootoutpb.NewShootoutServiceClient(conn)
-
-			// begin shootout
-			if _, err := client.ReceiveShootoutTime(ctx, &shootoutpb.ReceiveShootoutTimeRequest{Timestamp: shootoutTimestamp}); err != nil {
-				logger.Error("failed to send shootout beginning time", zap.Error(err))
+			if _, err := estimateClockOffset(ctx, logger, cfg, shootoutpb.NewShootoutServiceClient(conn), id); err != nil {
+				logger.Warn("failed to sync clock", zap.Error(err))
 			}
-
-			conn.Close()
-		}(ctx, i)
+		}(id)
 	}
 
 	wg.Wait()
 }
+
+// dialCowboy connects to a cowboy's gRPC server
+func dialCowboy(ctx context.Context, cfg *Config, id int) (*grpc.ClientConn, error) {
+	address, err := cfg.Resolver.Resolve(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("resolve cowboy: %w", err)
+	}
+
+	conn, err := grpc.Dial(address,
+		cfg.Creds.DialOption(tlsconfig.CowboySPIFFEID(id)),
+		grpc.WithChainUnaryInterceptor(cfg.Interceptors...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dial cowboy: %w", err)
+	}
+
+	return conn, nil
+}
+
+// estimateClockOffset estimates how far the cowboy's clock is off and stores it for when the cowboy reads the shootout
+// time from the datastore, cowboys without SyncClock are assumed to be in sync
+func estimateClockOffset(ctx context.Context, logger *zap.Logger, cfg *Config, client shootoutpb.ShootoutServiceClient, id int) (time.Duration, error) {
+	sample, err := clocksync.Estimate(ctx, client, cfg.ClockSyncSamples)
+	if err != nil && status.Code(err) != codes.Unimplemented {
+		return 0, fmt.Errorf("sync clock: %w", err)
+	}
+
+	logger.Debug("estimated clock offset", zap.Duration("clock_offset", sample.Offset), zap.Duration("delay", sample.Delay))
+
+	if err := shootoutstarter.StoreClockOffset(ctx, cfg.DB, id, sample.Offset); err != nil {
+		logger.Warn("failed to store clock offset", zap.Error(err))
+	}
+
+	return sample.Offset, nil
+}
+
+// forfeit kills a cowboy so that it's neither targeted nor able to shoot
+func forfeit(db datastore.Datastore, id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := shootoutstarter.Forfeit(ctx, db, id); err != nil {
+		return fmt.Errorf("forfeit cowboy %d: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,306 @@
+package broadcastdispatcher_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wildwest/internal/barrier"
+	"wildwest/internal/broadcastdispatcher"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/handlers/shootouthandler"
+	"wildwest/internal/resolver"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	shootoutpb "wildwest/api/proto/shootout"
+)
+
+// startCowboy serves the ShootoutService, failing the first failures calls
+func startCowboy(t *testing.T, failures int32) string {
+	var calls int32
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			return nil, status.Error(codes.Unavailable, "not yet")
+		}
+
+		return handler(ctx, req)
+	}))
+	shootoutpb.RegisterShootoutServiceServer(grpcServer, shootouthandler.NewGRPC(shootoutstarter.New(zap.NewNop())))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go grpcServer.Serve(lis) //nolint:errcheck
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+// closedAddress returns an address nobody listens on
+func closedAddress(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	address := lis.Addr().String()
+	assert.NoError(t, lis.Close())
+
+	return address
+}
+
+func TestBroadcastShootoutTime(t *testing.T) {
+	tests := []struct {
+		name        string
+		quorum      int
+		wantErr     error
+		wantHealths []string
+		wantPhase   gamephase.Phase
+	}{
+		{"quorum reached", 2, nil, []string{"10", "10", "0"}, gamephase.Running},
+		{"quorum not reached", 0, broadcastdispatcher.ErrQuorumNotReached, []string{"10", "10", "10"}, gamephase.Aborted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+
+			for id := 0; id < 3; id++ {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), "10"))
+				assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+			}
+
+			addresses := []string{startCowboy(t, 0), startCowboy(t, 2), closedAddress(t)}
+
+			// execute
+			err := broadcastdispatcher.BroadcastShootoutTime(ctx, zap.NewNop(), &broadcastdispatcher.Config{
+				Replicas:               3,
+				DB:                     fakeDatastore,
+				ReadinessTimeout:       time.Second,
+				ReadinessTimeoutPolicy: utils.ReadinessTimeoutPolicyAbort,
+				Countdown:              time.Second,
+				ClockSyncSamples:       3,
+				Quorum:                 tc.quorum,
+				Resolver:               resolver.NewStatic(addresses),
+			})
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			// the unreachable cowboy forfeits once the start is confirmed
+			for id, wantHealth := range tc.wantHealths {
+				got, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id))
+				assert.NoError(t, err)
+				assert.Equal(t, wantHealth, got, "cowboy %d", id)
+			}
+
+			phase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantPhase, phase)
+
+			assertClockOffsetsStored(t, fakeDatastore, []bool{true, true, false})
+		})
+	}
+}
+
+// assertClockOffsetsStored checks which cowboys got their clock offsets stored
+func assertClockOffsetsStored(t *testing.T, db datastore.Datastore, want []bool) {
+	for id, wantStored := range want {
+		_, err := db.Get(context.Background(), shootoutstarter.ClockOffsetKey(id))
+		assert.Equal(t, wantStored, err == nil, "cowboy %d", id)
+	}
+}
+
+func TestBroadcastShootoutTimePull(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+
+	for id := 0; id < 3; id++ {
+		assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), "10"))
+		assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+	}
+
+	// cowboy 2 never reads the start time
+	started := make(chan int, 2)
+	for id := 0; id < 2; id++ {
+		go func(id int) {
+			assert.NoError(t, shootoutstarter.NewDatastore(zap.NewNop(), id, fakeDatastore, 10*time.Millisecond).WaitForShootout(ctx))
+			started <- id
+		}(id)
+	}
+
+	// execute
+	err := broadcastdispatcher.BroadcastShootoutTime(ctx, zap.NewNop(), &broadcastdispatcher.Config{
+		Replicas:               3,
+		DB:                     fakeDatastore,
+		ReadinessTimeout:       time.Second,
+		ReadinessTimeoutPolicy: utils.ReadinessTimeoutPolicyAbort,
+		StartMode:              utils.StartModePull,
+		Countdown:              300 * time.Millisecond,
+		ClockSyncSamples:       3,
+		Quorum:                 2,
+		Resolver:               resolver.NewStatic([]string{startCowboy(t, 0), startCowboy(t, 0), closedAddress(t)}),
+	})
+
+	// verify
+	assert.NoError(t, err)
+
+	// the game is running by the time the shootout begins
+	startAt, err := fakeDatastore.Get(ctx, shootoutstarter.StartAtKey)
+	assert.NoError(t, err)
+
+	startTime, err := time.Parse(time.RFC3339Nano, startAt)
+	assert.NoError(t, err)
+	assert.True(t, time.Now().Before(startTime))
+
+	assert.ElementsMatch(t, []int{0, 1}, []int{<-started, <-started})
+
+	for id, wantHealth := range []string{"10", "10", "0"} {
+		got, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id))
+		assert.NoError(t, err)
+		assert.Equal(t, wantHealth, got, "cowboy %d", id)
+	}
+
+	phase, err := gamephase.Get(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Running, phase)
+
+	// the clocks are synced before the cowboys read the start time
+	assertClockOffsetsStored(t, fakeDatastore, []bool{true, true, false})
+}
+
+func TestBroadcastShootoutTimeCanceled(t *testing.T) {
+	// setup, nobody registers as ready
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// execute
+	err := broadcastdispatcher.BroadcastShootoutTime(ctx, zap.NewNop(), &broadcastdispatcher.Config{
+		Replicas:               3,
+		DB:                     datastore.NewFakeClient(),
+		ReadinessTimeout:       time.Minute,
+		ReadinessTimeoutPolicy: utils.ReadinessTimeoutPolicyStart,
+		Countdown:              time.Second,
+	})
+
+	// verify
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBroadcastShootoutTimeStartRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		manualStart bool
+		readyIDs    []int
+		wantErr     error
+		wantPhase   gamephase.Phase
+	}{
+		{"start before all cowboys are ready", false, []int{0, 1}, nil, gamephase.Running},
+		{"manual start", true, []int{0, 1}, nil, gamephase.Running},
+		{"manual start without ready cowboys", true, nil, broadcastdispatcher.ErrNoCowboysReady, gamephase.Aborted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+
+			for _, id := range tc.readyIDs {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), "10"))
+				assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+
+				go shootoutstarter.NewDatastore(zap.NewNop(), id, fakeDatastore, 10*time.Millisecond).WaitForShootout(ctx) //nolint:errcheck
+			}
+
+			startRequest := broadcastdispatcher.NewStartRequest()
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				startRequest.Request()
+				startRequest.Request()
+			}()
+
+			// execute, the readiness timeout would abort the game
+			err := broadcastdispatcher.BroadcastShootoutTime(ctx, zap.NewNop(), &broadcastdispatcher.Config{
+				Replicas:               3,
+				DB:                     fakeDatastore,
+				ReadinessTimeout:       time.Minute,
+				ReadinessTimeoutPolicy: utils.ReadinessTimeoutPolicyAbort,
+				ManualStart:            tc.manualStart,
+				StartRequest:           startRequest,
+				StartMode:              utils.StartModePull,
+				Countdown:              100 * time.Millisecond,
+			})
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			phase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantPhase, phase)
+		})
+	}
+}
+
+func TestBroadcastShootoutTimeScheduled(t *testing.T) {
+	tests := []struct {
+		name          string
+		startIn       time.Duration
+		wantScheduled bool
+	}{
+		{"scheduled", 300 * time.Millisecond, true},
+		{"schedule missed", -time.Hour, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+
+			for id := 0; id < 2; id++ {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), "10"))
+				assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+
+				go shootoutstarter.NewDatastore(zap.NewNop(), id, fakeDatastore, 10*time.Millisecond).WaitForShootout(ctx) //nolint:errcheck
+			}
+
+			startAt := time.Now().Add(tc.startIn)
+
+			// execute
+			err := broadcastdispatcher.BroadcastShootoutTime(ctx, zap.NewNop(), &broadcastdispatcher.Config{
+				Replicas:               2,
+				DB:                     fakeDatastore,
+				ReadinessTimeout:       time.Second,
+				ReadinessTimeoutPolicy: utils.ReadinessTimeoutPolicyAbort,
+				StartMode:              utils.StartModePull,
+				Countdown:              100 * time.Millisecond,
+				StartAt:                startAt,
+			})
+
+			// verify
+			assert.NoError(t, err)
+
+			stored, err := fakeDatastore.Get(ctx, shootoutstarter.StartAtKey)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantScheduled, stored == shootoutstarter.FormatStartAt(startAt))
+
+			phase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, gamephase.Running, phase)
+		})
+	}
+}
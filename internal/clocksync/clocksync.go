@@ -0,0 +1,58 @@
+package clocksync
+
+import (
+	"context"
+	"time"
+	"wildwest/internal/utils"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shootoutpb "wildwest/api/proto/shootout"
+)
+
+const ErrNoSamples = utils.ConstError("no clock samples")
+
+// Sample is the result of a single NTP-style exchange
+type Sample struct {
+	// Offset is how far the remote clock is ahead of ours
+	Offset time.Duration
+	// Delay is the network round trip, excluding the time spent by the remote
+	Delay time.Duration
+}
+
+// NewSample computes the offset and delay of an exchange sent at t0 and received back at t3 on our clock, which the
+// remote received at t1 and answered at t2 on its clock
+func NewSample(t0, t1, t2, t3 time.Time) Sample {
+	return Sample{
+		Offset: (t1.Sub(t0) + t2.Sub(t3)) / 2,
+		Delay:  t3.Sub(t0) - t2.Sub(t1),
+	}
+}
+
+// Estimate runs samples exchanges with a cowboy and returns the one with the lowest delay, which is the least
+// affected by asymmetric network delays. A failed exchange's status error is returned as is
+func Estimate(ctx context.Context, client shootoutpb.ShootoutServiceClient, samples int) (Sample, error) {
+	if samples <= 0 {
+		return Sample{}, ErrNoSamples
+	}
+
+	var best Sample
+
+	for i := 0; i < samples; i++ {
+		t0 := time.Now()
+
+		resp, err := client.SyncClock(ctx, &shootoutpb.SyncClockRequest{SentAt: timestamppb.New(t0)})
+		if err != nil {
+			return Sample{}, err
+		}
+
+		t3 := time.Now()
+
+		sample := NewSample(t0, resp.GetReceivedAt().AsTime(), resp.GetSentAt().AsTime(), t3)
+		if i == 0 || sample.Delay < best.Delay {
+			best = sample
+		}
+	}
+
+	return best, nil
+}
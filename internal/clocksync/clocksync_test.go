@@ -0,0 +1,41 @@
+package clocksync_test
+
+import (
+	"testing"
+	"time"
+	"wildwest/internal/clocksync"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSample(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+
+	tests := []struct {
+		name       string
+		remoteSkew time.Duration
+		there      time.Duration
+		processing time.Duration
+		back       time.Duration
+		wantOffset time.Duration
+		wantDelay  time.Duration
+	}{
+		{"synchronized clocks", 0, 5 * time.Millisecond, time.Millisecond, 5 * time.Millisecond, 0, 10 * time.Millisecond},
+		{"remote ahead", 300 * time.Millisecond, 5 * time.Millisecond, time.Millisecond, 5 * time.Millisecond, 300 * time.Millisecond, 10 * time.Millisecond},
+		{"remote behind", -2 * time.Second, 5 * time.Millisecond, 0, 5 * time.Millisecond, -2 * time.Second, 10 * time.Millisecond},
+		{"asymmetric delay", 0, 8 * time.Millisecond, 0, 2 * time.Millisecond, 3 * time.Millisecond, 10 * time.Millisecond},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t1 := t0.Add(tc.there).Add(tc.remoteSkew)
+			t2 := t1.Add(tc.processing)
+			t3 := t0.Add(tc.there + tc.processing + tc.back)
+
+			sample := clocksync.NewSample(t0, t1, t2, t3)
+
+			assert.Equal(t, tc.wantOffset, sample.Offset)
+			assert.Equal(t, tc.wantDelay, sample.Delay)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package damageapplier
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// flushTimeout bounds the datastore calls of a batch, which don't belong to a single caller
+const flushTimeout = 5 * time.Second
+
+// CoalescingDamageApplier merges the shots received within a window into a single transaction applying their
+// total damage, while every shot still gets its own result
+type CoalescingDamageApplier struct {
+	logger       *zap.Logger
+	id           int
+	db           datastore.Datastore
+	window       time.Duration
+	maxBatch     int
+	observerFunc func()
+
+	// single applies shots one by one when a batch can't be committed
+	single *DefaultDamageApplier
+
+	mu      *sync.Mutex
+	pending []*pendingShot
+	timer   *time.Timer
+
+	// flushMu serializes batches so that every batch sees the health left by the previous one
+	flushMu *sync.Mutex
+}
+
+var _ DamageApplier = (*CoalescingDamageApplier)(nil)
+
+type pendingShot struct {
+	from   int
+	damage int
+	result chan shotResult
+}
+
+type shotResult struct {
+	health int
+	err    error
+}
+
+// NewCoalescing creates a damage applier committing the shots received within window together, a batch is
+// committed early once it holds maxBatch shots
+func NewCoalescing(logger *zap.Logger, id int, db datastore.Datastore, window time.Duration, maxBatch int, observerFunc func()) *CoalescingDamageApplier {
+	return &CoalescingDamageApplier{
+		logger:       logger,
+		id:           id,
+		db:           db,
+		window:       window,
+		maxBatch:     maxBatch,
+		observerFunc: observerFunc,
+		single:       New(logger, id, db, observerFunc),
+		mu:           &sync.Mutex{},
+		flushMu:      &sync.Mutex{},
+	}
+}
+
+// ApplyDamage queues the shot into the current batch and waits for the batch to be committed
+func (cda *CoalescingDamageApplier) ApplyDamage(ctx context.Context, from, damage int) (int, error) {
+	shot := &pendingShot{
+		from:   from,
+		damage: damage,
+		result: make(chan shotResult, 1),
+	}
+
+	cda.mu.Lock()
+	cda.pending = append(cda.pending, shot)
+
+	switch {
+	case len(cda.pending) >= cda.maxBatch:
+		batch := cda.takeBatchNoLock()
+		cda.mu.Unlock()
+
+		go cda.flush(batch)
+	case len(cda.pending) == 1:
+		cda.timer = time.AfterFunc(cda.window, func() {
+			cda.mu.Lock()
+			batch := cda.takeBatchNoLock()
+			cda.mu.Unlock()
+
+			cda.flush(batch)
+		})
+		cda.mu.Unlock()
+	default:
+		cda.mu.Unlock()
+	}
+
+	select {
+	case result := <-shot.result:
+		return result.health, result.err
+	case <-ctx.Done():
+		// the shot may still be applied with its batch
+		return 0, ctx.Err()
+	}
+}
+
+// takeBatchNoLock removes the pending shots and stops the window timer
+func (cda *CoalescingDamageApplier) takeBatchNoLock() []*pendingShot {
+	if cda.timer != nil {
+		cda.timer.Stop()
+		cda.timer = nil
+	}
+
+	batch := cda.pending
+	cda.pending = nil
+
+	return batch
+}
+
+// flush commits a batch in one transaction and hands every shot its result in arrival order
+func (cda *CoalescingDamageApplier) flush(batch []*pendingShot) {
+	if len(batch) == 0 {
+		return
+	}
+
+	cda.flushMu.Lock()
+	defer cda.flushMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	logger := cda.logger.With(zap.Int("batch_size", len(batch)))
+
+	err := cda.commitBatch(ctx, logger, batch)
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		// a shooter or we are dead, our health changed or the game isn't running, apply the shots one by one to tell
+		// which of them fail
+		logger.Debug("batch transaction unsuccessful, applying shots one by one")
+
+		for _, shot := range batch {
+			health, err := cda.single.ApplyDamage(ctx, shot.from, shot.damage)
+			shot.result <- shotResult{health: health, err: err}
+		}
+
+		return
+	}
+
+	if err != nil {
+		for _, shot := range batch {
+			shot.result <- shotResult{err: err}
+		}
+	}
+}
+
+// commitBatch applies the total damage of the batch, the shot bringing the health to 0 is the killing shot and the
+// shots after it hit a dead target
+func (cda *CoalescingDamageApplier) commitBatch(ctx context.Context, logger *zap.Logger, batch []*pendingShot) error {
+	receiverHealth, err := cda.single.GetHealth(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the batch fails if our health changed since we read it, e.g. by the game master, the shots are then applied one
+	// by one to the changed health
+	cmps := []datastore.Cmp{
+		datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(cda.id), "=", strconv.Itoa(receiverHealth)),
+		datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(cda.id), ">", "0"),
+		cmpGameRunning(),
+	}
+	shooters := make(map[int]bool)
+
+	results := make([]shotResult, len(batch))
+	health := receiverHealth
+	killer := NoKiller
+
+	for i, shot := range batch {
+		if !shooters[shot.from] {
+			shooters[shot.from] = true
+			cmps = append(cmps, datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(shot.from), ">", "0"))
+		}
+
+		if health <= 0 {
+			results[i] = shotResult{err: datastore.ErrTransactionUnsuccessful}
+			continue
+		}
+
+		health -= shot.damage
+		if health <= 0 {
+			health = 0
+			killer = shot.from
+		}
+
+		results[i] = shotResult{health: health}
+	}
+
+	ops := []datastore.Op{datastore.OpPut(utils.CowboyKeyPrefix+strconv.Itoa(cda.id), strconv.Itoa(health))}
+	if health <= 0 {
+		ops = append(ops, OpRecordDeath(cda.id, killer))
+	}
+
+	err = cda.db.Transaction(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		return err
+	}
+
+	for i, shot := range batch {
+		shotLogger := logger.With(
+			zap.Int("from", shot.from),
+			zap.Int("damage", shot.damage),
+			zap.Int("health", results[i].health),
+		)
+
+		switch {
+		case results[i].err != nil:
+			shotLogger.Info("shot received after death")
+		case results[i].health <= 0:
+			cda.observerFunc()
+			shotLogger.Info("killing shot received")
+		default:
+			shotLogger.Info("shot received")
+		}
+
+		shot.result <- results[i]
+	}
+
+	return nil
+}
+
+func (cda *CoalescingDamageApplier) GetHealth(ctx context.Context) (int, error) {
+	return cda.single.GetHealth(ctx)
+}
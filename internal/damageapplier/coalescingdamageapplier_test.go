@@ -0,0 +1,115 @@
+package damageapplier_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCoalescingApplyDamage(t *testing.T) {
+	type shot struct {
+		from   int
+		damage int
+	}
+
+	tests := []struct {
+		name           string
+		receiverHealth int
+		shooterHealths map[int]int
+		shots          []shot
+		wantHealths    []int
+		wantErrs       []bool
+		wantEndHealth  string
+		wantKilled     int
+	}{
+		{"batch", 20, map[int]int{2: 5, 3: 5}, []shot{{2, 3}, {3, 4}, {2, 3}}, []int{17, 13, 10}, []bool{false, false, false}, "10", 0},
+		{"killing shot in batch", 10, map[int]int{2: 5, 3: 5}, []shot{{2, 4}, {3, 8}, {2, 4}}, []int{6, 0, 0}, []bool{false, false, true}, "0", 1},
+		{"dead shooter in batch", 20, map[int]int{2: 5, 3: 0}, []shot{{2, 3}, {3, 4}, {2, 3}}, []int{17, 0, 14}, []bool{false, true, false}, "14", 0},
+		{"dead receiver", 0, map[int]int{2: 5}, []shot{{2, 3}, {2, 3}}, []int{0, 0}, []bool{true, true}, "0", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", strconv.Itoa(tc.receiverHealth)))
+			for id, health := range tc.shooterHealths {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), strconv.Itoa(health)))
+			}
+
+			killed := 0
+			damageApplier := damageapplier.NewCoalescing(zap.NewNop(), 1, fakeDatastore, time.Hour, len(tc.shots), func() { killed++ })
+
+			// execute, shots are queued in order and the last one fills the batch
+			healths := make([]int, len(tc.shots))
+			errs := make([]error, len(tc.shots))
+
+			wg := sync.WaitGroup{}
+			for i, s := range tc.shots {
+				wg.Add(1)
+
+				go func(i int, s shot) {
+					defer wg.Done()
+					healths[i], errs[i] = damageApplier.ApplyDamage(ctx, s.from, s.damage)
+				}(i, s)
+
+				// let the shot join the batch before the next one
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			wg.Wait()
+
+			// verify
+			for i := range tc.shots {
+				assert.Equal(t, tc.wantErrs[i], errs[i] != nil, "shot %d: %v", i, errs[i])
+				assert.Equal(t, tc.wantHealths[i], healths[i], "shot %d", i)
+			}
+
+			got, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantEndHealth, got)
+			assert.Equal(t, tc.wantKilled, killed)
+		})
+	}
+}
+
+func TestCoalescingApplyDamageParallel(t *testing.T) {
+	// setup
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(context.Background(), gamephase.Key, string(gamephase.Running)))
+
+	assert.NoError(t, fakeDatastore.Put(context.Background(), utils.CowboyKeyPrefix+"1", "10000"))
+	assert.NoError(t, fakeDatastore.Put(context.Background(), utils.CowboyKeyPrefix+"2", "1"))
+
+	damageApplier := damageapplier.NewCoalescing(zap.NewNop(), 1, fakeDatastore, time.Millisecond, 16, func() {})
+
+	// execute
+	wg := sync.WaitGroup{}
+	wg.Add(100)
+
+	for i := 1; i <= 100; i++ {
+		go func(damage int) {
+			defer wg.Done()
+			_, _ = damageApplier.ApplyDamage(context.Background(), 2, damage)
+		}(i)
+	}
+
+	wg.Wait()
+
+	// verify
+	health, err := damageApplier.GetHealth(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 4950, health)
+}
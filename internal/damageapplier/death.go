@@ -0,0 +1,67 @@
+package damageapplier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wildwest/internal/datastore"
+)
+
+// DeathKeyPrefix prefixes the keys recording how the cowboys died
+const DeathKeyPrefix = "death-"
+
+// NoKiller is the killer of cowboys killed without being shot, e.g. by the game master
+const NoKiller = -1
+
+// DeathKey returns the datastore key recording how a cowboy died, e.g. death-1
+func DeathKey(id int) string {
+	return DeathKeyPrefix + strconv.Itoa(id)
+}
+
+// Death records who killed a cowboy and when, cowboys which forfeited have no death recorded
+type Death struct {
+	Killer int       `json:"killer"`
+	At     time.Time `json:"at"`
+}
+
+// OpRecordDeath records the death of a cowboy, to be applied with the transaction killing it
+func OpRecordDeath(id, killer int) datastore.Op {
+	// marshaling a struct of an int and a time can't fail
+	value, _ := json.Marshal(Death{Killer: killer, At: time.Now().UTC()})
+
+	return datastore.OpPut(DeathKey(id), string(value))
+}
+
+// Deaths returns the recorded deaths by the id of the dead cowboy
+func Deaths(ctx context.Context, db datastore.Datastore) (map[int]Death, error) {
+	kvs, err := db.GetPrefix(ctx, DeathKeyPrefix)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return map[int]Death{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get deaths: %w", err)
+	}
+
+	deaths := make(map[int]Death, len(kvs))
+
+	for key, value := range kvs {
+		id, err := strconv.Atoi(strings.TrimPrefix(key, DeathKeyPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("convert cowboy id to int: %w", err)
+		}
+
+		var death Death
+		if err := json.Unmarshal([]byte(value), &death); err != nil {
+			return nil, fmt.Errorf("unmarshal death of cowboy %d: %w", id, err)
+		}
+
+		deaths[id] = death
+	}
+
+	return deaths, nil
+}
@@ -2,14 +2,20 @@ package damageapplier
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"sync"
 	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
 	"wildwest/internal/utils"
 
 	"go.uber.org/zap"
 )
 
+// maxDamageAttempts bounds how often a shot is applied again because our health changed while applying it
+const maxDamageAttempts = 3
+
 type DefaultDamageApplier struct {
 	logger       *zap.Logger
 	id           int
@@ -39,22 +45,7 @@ func (da *DefaultDamageApplier) ApplyDamage(ctx context.Context, from, damage in
 		zap.Int("damage", damage),
 	)
 
-	receiverHealth, err := da.getHealthNoLock(ctx)
-	if err != nil {
-		return 0, err
-	}
-
-	newReceiverHealth := receiverHealth - damage
-	if newReceiverHealth < 0 {
-		newReceiverHealth = 0
-	}
-
-	err = da.db.Transaction(ctx).If(
-		datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(da.id), ">", "0"),
-		datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(from), ">", "0"),
-	).Then(
-		datastore.OpPut(utils.CowboyKeyPrefix+strconv.Itoa(da.id), strconv.Itoa(newReceiverHealth)),
-	).Commit()
+	newReceiverHealth, err := da.commitDamageNoLock(ctx, from, damage)
 	if err != nil {
 		return 0, err
 	}
@@ -73,6 +64,71 @@ func (da *DefaultDamageApplier) ApplyDamage(ctx context.Context, from, damage in
 	return newReceiverHealth, nil
 }
 
+// commitDamageNoLock subtracts the damage from the health we read unless it changed meanwhile, e.g. by the game master,
+// then the damage is subtracted from the changed health
+func (da *DefaultDamageApplier) commitDamageNoLock(ctx context.Context, from, damage int) (int, error) {
+	for attempt := 1; ; attempt++ {
+		receiverHealth, err := da.getHealthNoLock(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		newReceiverHealth := receiverHealth - damage
+		if newReceiverHealth < 0 {
+			newReceiverHealth = 0
+		}
+
+		ops := []datastore.Op{datastore.OpPut(utils.CowboyKeyPrefix+strconv.Itoa(da.id), strconv.Itoa(newReceiverHealth))}
+		if newReceiverHealth <= 0 {
+			ops = append(ops, OpRecordDeath(da.id, from))
+		}
+
+		err = da.db.Transaction(ctx).If(
+			datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(da.id), "=", strconv.Itoa(receiverHealth)),
+			datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(da.id), ">", "0"),
+			datastore.Compare(utils.CowboyKeyPrefix+strconv.Itoa(from), ">", "0"),
+			cmpGameRunning(),
+		).Then(ops...).Commit()
+		if err == nil {
+			return newReceiverHealth, nil
+		}
+
+		if !errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+			return 0, err
+		}
+
+		if attempt < maxDamageAttempts && da.healthChangedNoLock(ctx, receiverHealth) {
+			da.logger.Debug("health changed while applying damage, retrying", zap.Int("from", from), zap.Int("attempt", attempt))
+			continue
+		}
+
+		return 0, da.unsuccessfulError(ctx, err)
+	}
+}
+
+// healthChangedNoLock reports whether we're alive with another health than the one read before
+func (da *DefaultDamageApplier) healthChangedNoLock(ctx context.Context, read int) bool {
+	health, err := da.getHealthNoLock(ctx)
+
+	return err == nil && health > 0 && health != read
+}
+
+// cmpGameRunning makes a damage transaction fail unless the game is running, so that no damage is committed once it's
+// paused or over
+func cmpGameRunning() datastore.Cmp {
+	return datastore.Compare(gamephase.Key, "=", string(gamephase.Running))
+}
+
+// unsuccessfulError tells why a damage transaction failed, ErrGameNotRunning if the game isn't running, otherwise
+// either we or the shooter are dead and err is returned
+func (da *DefaultDamageApplier) unsuccessfulError(ctx context.Context, err error) error {
+	if phase, phaseErr := gamephase.Get(ctx, da.db); phaseErr == nil && phase != gamephase.Running {
+		return fmt.Errorf("%w: game is %s", ErrGameNotRunning, phase)
+	}
+
+	return err
+}
+
 func (da *DefaultDamageApplier) getHealthNoLock(ctx context.Context) (int, error) {
 	receiverHealthStr, err := da.db.Get(ctx, utils.CowboyKeyPrefix+strconv.Itoa(da.id))
 	if err != nil {
@@ -5,8 +5,10 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 	"wildwest/internal/damageapplier"
 	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
 	"wildwest/internal/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -25,9 +27,10 @@ func TestApplyDamage(t *testing.T) {
 		receiverID          int
 		receiverStartHealth int
 		receiverEndHealth   int
+		wantKiller          int
 		actions             []action
 	}{
-		{"shots below 0 health", 1, 20, 0, []action{
+		{"shots below 0 health", 1, 20, 0, 6, []action{
 			{2, 5, 2},
 			{2, 5, 2},
 			{2, 5, 2},
@@ -37,7 +40,7 @@ func TestApplyDamage(t *testing.T) {
 			{5, 1, 5},
 			{6, 3, 8},
 		}},
-		{"shots exactly to 0 health", 1, 20, 0, []action{
+		{"shots exactly to 0 health", 1, 20, 0, 2, []action{
 			{2, 5, 5},
 			{2, 5, 5},
 			{2, 5, 5},
@@ -49,6 +52,7 @@ func TestApplyDamage(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// setup
 			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(context.Background(), gamephase.Key, string(gamephase.Running)))
 
 			err := fakeDatastore.Put(context.Background(), utils.CowboyKeyPrefix+strconv.Itoa(tc.receiverID), strconv.Itoa(tc.receiverStartHealth))
 			assert.NoError(t, err)
@@ -69,6 +73,10 @@ func TestApplyDamage(t *testing.T) {
 			gotReceiverEndHealth, err := fakeDatastore.Get(context.Background(), utils.CowboyKeyPrefix+strconv.Itoa(tc.receiverID))
 			assert.NoError(t, err)
 			assert.Equal(t, strconv.Itoa(tc.receiverEndHealth), gotReceiverEndHealth)
+
+			deaths, err := damageapplier.Deaths(context.Background(), fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantKiller, deaths[tc.receiverID].Killer)
 		})
 	}
 }
@@ -88,6 +96,7 @@ func TestApplyDamageParallel(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// setup
 			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(context.Background(), gamephase.Key, string(gamephase.Running)))
 
 			err := fakeDatastore.Put(context.Background(), utils.CowboyKeyPrefix+"1", strconv.Itoa(tc.startHealth))
 			assert.NoError(t, err)
@@ -117,3 +126,104 @@ func TestApplyDamageParallel(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyDamageGameNotRunning(t *testing.T) {
+	tests := []struct {
+		name       string
+		coalesced  bool
+		phase      gamephase.Phase
+		wantErr    error
+		wantHealth string
+	}{
+		{"running", false, gamephase.Running, nil, "7"},
+		{"paused", false, gamephase.Paused, damageapplier.ErrGameNotRunning, "10"},
+		{"finished", false, gamephase.Finished, damageapplier.ErrGameNotRunning, "10"},
+		{"coalesced while running", true, gamephase.Running, nil, "7"},
+		{"coalesced while aborted", true, gamephase.Aborted, damageapplier.ErrGameNotRunning, "10"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.phase)))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "10"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", "10"))
+
+			var damageApplier damageapplier.DamageApplier = damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {})
+			if tc.coalesced {
+				damageApplier = damageapplier.NewCoalescing(zap.NewNop(), 1, fakeDatastore, time.Millisecond, 8, func() {})
+			}
+
+			// execute
+			_, err := damageApplier.ApplyDamage(ctx, 2, 3)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			health, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHealth, health)
+		})
+	}
+}
+
+// interveningDatastore sets the health of cowboy 1 right before the first transaction, like a game master
+// intervention committed while a shot is applied
+type interveningDatastore struct {
+	datastore.Datastore
+	health string
+	once   sync.Once
+}
+
+func (ids *interveningDatastore) Transaction(ctx context.Context) datastore.Transaction {
+	ids.once.Do(func() {
+		_ = ids.Datastore.Put(ctx, utils.CowboyKeyPrefix+"1", ids.health)
+	})
+
+	return ids.Datastore.Transaction(ctx)
+}
+
+func TestApplyDamageHealthChanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		coalesced  bool
+		health     string
+		wantErr    error
+		wantHealth string
+	}{
+		{"health set", false, "50", nil, "47"},
+		{"killed", false, "0", datastore.ErrTransactionUnsuccessful, "0"},
+		{"coalesced with health set", true, "50", nil, "47"},
+		{"coalesced when killed", true, "0", datastore.ErrTransactionUnsuccessful, "0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "100"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", "10"))
+
+			db := &interveningDatastore{Datastore: fakeDatastore, health: tc.health}
+
+			var damageApplier damageapplier.DamageApplier = damageapplier.New(zap.NewNop(), 1, db, func() {})
+			if tc.coalesced {
+				damageApplier = damageapplier.NewCoalescing(zap.NewNop(), 1, db, time.Millisecond, 8, func() {})
+			}
+
+			// execute
+			_, err := damageApplier.ApplyDamage(ctx, 2, 3)
+
+			// verify, the intervention isn't overwritten
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			health, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHealth, health)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package damageapplier
+
+import (
+	"context"
+	"fmt"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/utils"
+)
+
+const ErrGameNotRunning = utils.ConstError("game isn't running")
+
+// PhaseGatedDamageApplier rejects shots early while our view of the game phase isn't running, the damage transaction
+// checks the stored phase as our view may be a poll interval behind
+type PhaseGatedDamageApplier struct {
+	next      DamageApplier
+	gamePhase *gamephase.Watcher
+}
+
+var _ DamageApplier = (*PhaseGatedDamageApplier)(nil)
+
+func NewPhaseGated(next DamageApplier, gamePhase *gamephase.Watcher) *PhaseGatedDamageApplier {
+	return &PhaseGatedDamageApplier{
+		next:      next,
+		gamePhase: gamePhase,
+	}
+}
+
+func (pgda *PhaseGatedDamageApplier) ApplyDamage(ctx context.Context, from, damage int) (int, error) {
+	if phase := pgda.gamePhase.Phase(); phase != gamephase.Running {
+		return 0, fmt.Errorf("%w: game is %s", ErrGameNotRunning, phase)
+	}
+
+	return pgda.next.ApplyDamage(ctx, from, damage)
+}
+
+func (pgda *PhaseGatedDamageApplier) GetHealth(ctx context.Context) (int, error) {
+	return pgda.next.GetHealth(ctx)
+}
@@ -0,0 +1,60 @@
+package damageapplier_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPhaseGatedApplyDamage(t *testing.T) {
+	tests := []struct {
+		phase      gamephase.Phase
+		wantErr    error
+		wantHealth string
+	}{
+		{gamephase.Registering, damageapplier.ErrGameNotRunning, "10"},
+		{gamephase.Countdown, damageapplier.ErrGameNotRunning, "10"},
+		{gamephase.Running, nil, "7"},
+		{gamephase.Paused, damageapplier.ErrGameNotRunning, "10"},
+		{gamephase.Finished, damageapplier.ErrGameNotRunning, "10"},
+		{gamephase.Aborted, damageapplier.ErrGameNotRunning, "10"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.phase), func(t *testing.T) {
+			// setup
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "10"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", "10"))
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.phase)))
+
+			watcher := gamephase.NewWatcher(zap.NewNop(), fakeDatastore, time.Millisecond, nil)
+			go watcher.Run(ctx)
+
+			_, err := watcher.Wait(ctx, tc.phase)
+			assert.NoError(t, err)
+
+			damageApplier := damageapplier.NewPhaseGated(damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {}), watcher)
+
+			// execute
+			_, err = damageApplier.ApplyDamage(ctx, 2, 3)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			health, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHealth, health)
+		})
+	}
+}
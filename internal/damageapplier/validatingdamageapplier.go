@@ -0,0 +1,133 @@
+package damageapplier
+
+import (
+	"context"
+	"sync"
+	"time"
+	"wildwest/internal/events"
+	"wildwest/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ErrUnknownShooter    = utils.ConstError("unknown shooter")
+	ErrDamageNotPositive = utils.ConstError("damage must be positive")
+	ErrDamageMismatch    = utils.ConstError("damage doesn't match the shooter's damage")
+	ErrFireRateExceeded  = utils.ConstError("shooter exceeded its fire rate")
+)
+
+// ValidatingDamageApplier checks shots against the roster and the fire rate before applying them
+type ValidatingDamageApplier struct {
+	next         DamageApplier
+	roster       []utils.Cowboy
+	fireInterval time.Duration
+	burst        int
+	recorder     *events.Recorder
+
+	mu      *sync.Mutex
+	buckets map[int]*tokenBucket
+}
+
+var _ DamageApplier = (*ValidatingDamageApplier)(nil)
+
+// NewValidating wraps next, every shooter may fire once per fireInterval with bursts of up to burst shots
+func NewValidating(next DamageApplier, roster []utils.Cowboy, fireInterval time.Duration, burst int, recorder *events.Recorder) *ValidatingDamageApplier {
+	return &ValidatingDamageApplier{
+		next:         next,
+		roster:       roster,
+		fireInterval: fireInterval,
+		burst:        burst,
+		recorder:     recorder,
+		mu:           &sync.Mutex{},
+		buckets:      make(map[int]*tokenBucket),
+	}
+}
+
+func (vda *ValidatingDamageApplier) ApplyDamage(ctx context.Context, from, damage int) (int, error) {
+	if err := vda.validate(from, damage); err != nil {
+		return 0, err
+	}
+
+	return vda.next.ApplyDamage(ctx, from, damage)
+}
+
+func (vda *ValidatingDamageApplier) GetHealth(ctx context.Context) (int, error) {
+	return vda.next.GetHealth(ctx)
+}
+
+// validate checks the shot and records a violation event if it is rejected
+func (vda *ValidatingDamageApplier) validate(from, damage int) error {
+	if from < 0 || from >= len(vda.roster) {
+		vda.recordViolation(events.TypeUnknownShooter, from, damage)
+		return ErrUnknownShooter
+	}
+
+	if damage <= 0 {
+		vda.recordViolation(events.TypeDamageNotPositive, from, damage)
+		return ErrDamageNotPositive
+	}
+
+	if int64(damage) != vda.roster[from].Damage {
+		vda.recordViolation(events.TypeDamageMismatch, from, damage, zap.Int64("expected_damage", vda.roster[from].Damage))
+		return ErrDamageMismatch
+	}
+
+	if !vda.take(from) {
+		vda.recordViolation(events.TypeFireRateExceeded, from, damage, zap.Duration("fire_interval", vda.fireInterval))
+		return ErrFireRateExceeded
+	}
+
+	return nil
+}
+
+// take takes a token from the shooter's bucket, returns false if the bucket is empty
+func (vda *ValidatingDamageApplier) take(from int) bool {
+	vda.mu.Lock()
+	defer vda.mu.Unlock()
+
+	bucket, ok := vda.buckets[from]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(vda.burst), updatedAt: time.Now()}
+		vda.buckets[from] = bucket
+	}
+
+	return bucket.take(time.Now(), vda.fireInterval, vda.burst)
+}
+
+func (vda *ValidatingDamageApplier) recordViolation(eventType string, from, damage int, fields ...zap.Field) {
+	vda.recorder.Record(events.Event{
+		Type:    eventType,
+		Cowboy:  from,
+		Message: "rejected shot",
+		Fields:  append([]zap.Field{zap.Int("damage", damage)}, fields...),
+	})
+}
+
+// tokenBucket refills one token per interval up to burst tokens
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func (tb *tokenBucket) take(now time.Time, interval time.Duration, burst int) bool {
+	if interval > 0 {
+		tb.tokens += float64(now.Sub(tb.updatedAt)) / float64(interval)
+	} else {
+		tb.tokens = float64(burst)
+	}
+
+	if tb.tokens > float64(burst) {
+		tb.tokens = float64(burst)
+	}
+
+	tb.updatedAt = now
+
+	if tb.tokens < 1 {
+		return false
+	}
+
+	tb.tokens--
+
+	return true
+}
@@ -0,0 +1,77 @@
+package damageapplier_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/events"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/metrics"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestValidatingApplyDamage(t *testing.T) {
+	roster := []utils.Cowboy{
+		{Name: "John", Health: 100, Damage: 1},
+		{Name: "Bill", Health: 100, Damage: 2},
+	}
+
+	type shot struct {
+		from   int
+		damage int
+		err    error
+	}
+
+	tests := []struct {
+		name      string
+		shots     []shot
+		wantEvent string
+	}{
+		{"valid shot", []shot{{1, 2, nil}}, ""},
+		{"unknown shooter", []shot{{5, 2, damageapplier.ErrUnknownShooter}}, events.TypeUnknownShooter},
+		{"negative damage", []shot{{1, -2, damageapplier.ErrDamageNotPositive}}, events.TypeDamageNotPositive},
+		{"inflated damage", []shot{{1, 50, damageapplier.ErrDamageMismatch}}, events.TypeDamageMismatch},
+		{"fire rate exceeded", []shot{
+			{1, 2, nil},
+			{1, 2, nil},
+			{1, 2, damageapplier.ErrFireRateExceeded},
+		}, events.TypeFireRateExceeded},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "100"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "100"))
+
+			registry := metrics.New()
+
+			damageApplier := damageapplier.NewValidating(
+				damageapplier.New(zap.NewNop(), 0, fakeDatastore, func() {}),
+				roster,
+				time.Hour,
+				2,
+				events.New(zap.NewNop(), registry),
+			)
+
+			// execute and verify
+			for _, s := range tc.shots {
+				_, err := damageApplier.ApplyDamage(ctx, s.from, s.damage)
+				assert.ErrorIs(t, err, s.err)
+			}
+
+			if tc.wantEvent != "" {
+				assert.Equal(t, uint64(1), registry.Counter(events.EventsMetric, "type", tc.wantEvent))
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 	"wildwest/internal/utils"
@@ -96,11 +97,13 @@ func (ecw *EtcdClientWrapper) Close() error {
 	return ecw.closeConnectionFunc()
 }
 
-func InitEtcdDatastore(etcdEndpoint string) (*EtcdClientWrapper, error) {
+// InitEtcdDatastore connects to etcd, tlsConfig is nil for plaintext connections
+func InitEtcdDatastore(etcdEndpoint string, tlsConfig *tls.Config) (*EtcdClientWrapper, error) {
 	etcdEndpoints := []string{etcdEndpoint}
 	cfg := etcdClient.Config{
 		Endpoints:   etcdEndpoints,
 		DialTimeout: time.Minute,
+		TLS:         tlsConfig,
 	}
 
 	etcdC, err := etcdClient.New(cfg)
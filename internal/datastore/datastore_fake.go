@@ -66,6 +66,18 @@ func (fc *FakeClient) Put(ctx context.Context, key string, value string) error {
 	return nil
 }
 
+// delete deletes the key, or all keys with the prefix
+func (fc *FakeClient) delete(key string, prefix bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	for k := range fc.kvStorage {
+		if k == key || prefix && strings.HasPrefix(k, key) {
+			delete(fc.kvStorage, k)
+		}
+	}
+}
+
 func (fc *FakeClient) Transaction(ctx context.Context) Transaction {
 	return &TxnFake{
 		datastore: fc,
@@ -8,6 +8,8 @@ import (
 
 const (
 	OpTypePut                  = "put"
+	OpTypeDelete               = "delete"
+	OpTypeDeletePrefix         = "delete-prefix"
 	ErrTransactionUnsuccessful = utils.ConstError("transaction unsuccessful")
 )
 
@@ -16,6 +18,8 @@ type Cmp struct {
 	key      string
 	operator string
 	value    string
+	// absent compares whether the key doesn't exist instead of its value
+	absent bool
 }
 
 // Op represents an operation in a transaction
@@ -27,12 +31,13 @@ type Op struct {
 
 type Transaction interface {
 	If(...Cmp) Transaction
-	Then(Op) Transaction
+	Then(...Op) Transaction
 	Commit() error
 }
 
 type Txn struct {
 	etcdTxn etcdClient.Txn
+	ops     []etcdClient.Op
 }
 
 var _ Transaction = (*Txn)(nil)
@@ -46,6 +51,14 @@ func Compare(key string, operator string, value string) Cmp {
 	}
 }
 
+// CompareAbsent creates a new Cmp instance which succeeds if the key doesn't exist
+func CompareAbsent(key string) Cmp {
+	return Cmp{
+		key:    key,
+		absent: true,
+	}
+}
+
 // OpPut creates a new Op instance for a put operation
 func OpPut(key string, value string) Op {
 	return Op{
@@ -55,10 +68,31 @@ func OpPut(key string, value string) Op {
 	}
 }
 
+// OpDelete creates a new Op instance for a delete operation, deleting a missing key succeeds
+func OpDelete(key string) Op {
+	return Op{
+		opType: OpTypeDelete,
+		key:    key,
+	}
+}
+
+// OpDeletePrefix creates a new Op instance deleting all keys with the given prefix
+func OpDeletePrefix(prefix string) Op {
+	return Op{
+		opType: OpTypeDeletePrefix,
+		key:    prefix,
+	}
+}
+
 // If adds comparisons to the transaction and returns the updated transaction
 func (t *Txn) If(cmps ...Cmp) Transaction {
 	etcdCmps := make([]etcdClient.Cmp, 0, len(cmps))
 	for _, cmp := range cmps {
+		if cmp.absent {
+			etcdCmps = append(etcdCmps, etcdClient.Compare(etcdClient.CreateRevision(cmp.key), "=", 0))
+			continue
+		}
+
 		etcdCmps = append(etcdCmps, etcdClient.Compare(etcdClient.Value(cmp.key), cmp.operator, cmp.value))
 	}
 
@@ -67,10 +101,17 @@ func (t *Txn) If(cmps ...Cmp) Transaction {
 	return t
 }
 
-// Then adds an operation to the transaction and returns the updated transaction
-func (t *Txn) Then(op Op) Transaction {
-	if op.opType == OpTypePut {
-		t.etcdTxn = t.etcdTxn.Then(etcdClient.OpPut(op.key, op.value))
+// Then adds operations to the transaction and returns the updated transaction, they're applied together on commit
+func (t *Txn) Then(ops ...Op) Transaction {
+	for _, op := range ops {
+		switch op.opType {
+		case OpTypePut:
+			t.ops = append(t.ops, etcdClient.OpPut(op.key, op.value))
+		case OpTypeDelete:
+			t.ops = append(t.ops, etcdClient.OpDelete(op.key))
+		case OpTypeDeletePrefix:
+			t.ops = append(t.ops, etcdClient.OpDelete(op.key, etcdClient.WithPrefix()))
+		}
 	}
 
 	return t
@@ -78,7 +119,7 @@ func (t *Txn) Then(op Op) Transaction {
 
 // Commit attempts to commit the transaction and returns an error if unsuccessful
 func (t *Txn) Commit() error {
-	resp, err := t.etcdTxn.Commit()
+	resp, err := t.etcdTxn.Then(t.ops...).Commit()
 	if err != nil {
 		return err
 	}
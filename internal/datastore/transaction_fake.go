@@ -7,7 +7,7 @@ import (
 type TxnFake struct {
 	datastore *FakeClient
 	cmps      []Cmp
-	op        Op
+	ops       []Op
 
 	ctx context.Context
 }
@@ -21,9 +21,9 @@ func (tf *TxnFake) If(cmps ...Cmp) Transaction {
 	return tf
 }
 
-// Then adds an operation to the transaction and returns the updated transaction
-func (tf *TxnFake) Then(op Op) Transaction {
-	tf.op = op
+// Then adds operations to the transaction and returns the updated transaction
+func (tf *TxnFake) Then(ops ...Op) Transaction {
+	tf.ops = append(tf.ops, ops...)
 
 	return tf
 }
@@ -38,10 +38,46 @@ func (tf *TxnFake) Commit() error {
 	defer tf.datastore.GetDBMu().Unlock()
 
 	for _, cmp := range tf.cmps {
-		if val, _ := tf.datastore.Get(context.Background(), cmp.key); val <= cmp.value {
+		if !tf.compare(cmp) {
 			return ErrTransactionUnsuccessful
 		}
 	}
 
-	return tf.datastore.Put(context.Background(), tf.op.key, tf.op.value)
+	for _, op := range tf.ops {
+		switch op.opType {
+		case OpTypeDelete:
+			tf.datastore.delete(op.key, false)
+		case OpTypeDeletePrefix:
+			tf.datastore.delete(op.key, true)
+		default:
+			if err := tf.datastore.Put(context.Background(), op.key, op.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// compare compares values like etcd does, comparing the value of a missing key always fails
+func (tf *TxnFake) compare(cmp Cmp) bool {
+	val, err := tf.datastore.Get(context.Background(), cmp.key)
+	if cmp.absent {
+		return err != nil
+	}
+
+	if err != nil {
+		return false
+	}
+
+	switch cmp.operator {
+	case "=":
+		return val == cmp.value
+	case "!=":
+		return val != cmp.value
+	case "<":
+		return val < cmp.value
+	default:
+		return val > cmp.value
+	}
 }
@@ -61,6 +61,57 @@ func TestTransactions(t *testing.T) {
 			wantErr: datastore.ErrTransactionUnsuccessful,
 			want:    "1",
 		},
+		{
+			name: "successful transaction comparing equality",
+			setupValues: map[string]string{
+				"1":   "running",
+				opKey: "1",
+			},
+			cmps: []datastore.Cmp{
+				datastore.Compare("1", "=", "running"),
+			},
+			op:      datastore.OpPut(opKey, "0"),
+			wantErr: nil,
+			want:    "0",
+		},
+		{
+			name: "unsuccessful transaction comparing equality",
+			setupValues: map[string]string{
+				"1":   "paused",
+				opKey: "1",
+			},
+			cmps: []datastore.Cmp{
+				datastore.Compare("1", "=", "running"),
+			},
+			op:      datastore.OpPut(opKey, "0"),
+			wantErr: datastore.ErrTransactionUnsuccessful,
+			want:    "1",
+		},
+		{
+			name: "successful transaction checking absent key",
+			setupValues: map[string]string{
+				opKey: "1",
+			},
+			cmps: []datastore.Cmp{
+				datastore.CompareAbsent("1"),
+			},
+			op:      datastore.OpPut(opKey, "0"),
+			wantErr: nil,
+			want:    "0",
+		},
+		{
+			name: "unsuccessful transaction checking absent key",
+			setupValues: map[string]string{
+				"1":   "0",
+				opKey: "1",
+			},
+			cmps: []datastore.Cmp{
+				datastore.CompareAbsent("1"),
+			},
+			op:      datastore.OpPut(opKey, "0"),
+			wantErr: datastore.ErrTransactionUnsuccessful,
+			want:    "1",
+		},
 	}
 
 	for _, tc := range tests {
@@ -87,3 +138,48 @@ func TestTransactions(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionMultipleOps(t *testing.T) {
+	// setup
+	fakeDatastore := datastore.NewFakeClient()
+	ctx := context.Background()
+
+	// execute
+	err := fakeDatastore.Transaction(ctx).
+		If(datastore.CompareAbsent(opKey)).
+		Then(datastore.OpPut(opKey, "1"), datastore.OpPut("2", "2")).
+		Commit()
+
+	// verify
+	assert.NoError(t, err)
+
+	for key, want := range map[string]string{opKey: "1", "2": "2"} {
+		got, err := fakeDatastore.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestTransactionDelete(t *testing.T) {
+	// setup
+	fakeDatastore := datastore.NewFakeClient()
+	ctx := context.Background()
+
+	for _, key := range []string{opKey, "ready-1", "ready-2", "readiness"} {
+		assert.NoError(t, fakeDatastore.Put(ctx, key, "1"))
+	}
+
+	// execute
+	err := fakeDatastore.Transaction(ctx).
+		If(datastore.Compare(opKey, "=", "1")).
+		Then(datastore.OpDelete(opKey), datastore.OpDeletePrefix("ready-"), datastore.OpDelete("missing")).
+		Commit()
+
+	// verify
+	assert.NoError(t, err)
+
+	for key, wantExists := range map[string]bool{opKey: false, "ready-1": false, "ready-2": false, "readiness": true} {
+		_, err := fakeDatastore.Get(ctx, key)
+		assert.Equal(t, wantExists, err == nil, key)
+	}
+}
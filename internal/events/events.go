@@ -0,0 +1,64 @@
+package events
+
+import (
+	"wildwest/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// EventsMetric counts the recorded events by type
+const EventsMetric = "wildwest_events_total"
+
+const (
+	TypeUnknownShooter    = "unknown_shooter"
+	TypeDamageNotPositive = "damage_not_positive"
+	TypeDamageMismatch    = "damage_mismatch"
+	TypeFireRateExceeded  = "fire_rate_exceeded"
+)
+
+// game master interventions
+const (
+	TypeHealthSet = "health_set"
+	TypeRevived   = "revived"
+	TypeKicked    = "kicked"
+	TypeSmitten   = "smitten"
+)
+
+// Event is a notable thing a cowboy did or that was done to it, e.g. a rule violation or a game master intervention
+type Event struct {
+	Type string
+	// Cowboy is the id of the cowboy the event is about
+	Cowboy  int
+	Message string
+	Fields  []zap.Field
+}
+
+// Recorder logs events as structured log entries and counts them, a nil Recorder drops events
+type Recorder struct {
+	logger  *zap.Logger
+	metrics *metrics.Registry
+}
+
+func New(logger *zap.Logger, registry *metrics.Registry) *Recorder {
+	return &Recorder{
+		logger:  logger,
+		metrics: registry,
+	}
+}
+
+func (r *Recorder) Record(event Event) {
+	if r == nil {
+		return
+	}
+
+	fields := append([]zap.Field{
+		zap.String("event_type", event.Type),
+		zap.Int("cowboy", event.Cowboy),
+	}, event.Fields...)
+
+	r.logger.Warn(event.Message, fields...)
+
+	if r.metrics != nil {
+		r.metrics.Inc(EventsMetric, "type", event.Type)
+	}
+}
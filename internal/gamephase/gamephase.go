@@ -0,0 +1,248 @@
+package gamephase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/utils"
+)
+
+const (
+	ErrInvalidTransition = utils.ConstError("invalid game phase transition")
+	ErrPhaseChanged      = utils.ConstError("game phase changed")
+)
+
+// Key holds the game phase, SinceKey the time it was entered and PausedKey for how long the game was paused before the
+// current pause
+const (
+	Key       = "game-phase"
+	SinceKey  = "game-phase-since"
+	PausedKey = "game-paused"
+)
+
+// Phase is the phase of the whole game, unlike healthcheck.Phase which is the phase of a single cowboy
+type Phase string
+
+const (
+	// Registering waits for the cowboys to get ready
+	Registering Phase = "registering"
+	// Countdown delivers the shootout time to the cowboys
+	Countdown Phase = "countdown"
+	// Running is the only phase in which cowboys shoot
+	Running Phase = "running"
+	// Paused freezes a running game
+	Paused Phase = "paused"
+	// Finished is entered once there's a winner
+	Finished Phase = "finished"
+	// Aborted is entered if the game can't go on
+	Aborted Phase = "aborted"
+)
+
+// transitions lists the phases every phase may move to
+var transitions = map[Phase][]Phase{
+	Registering: {Countdown, Aborted},
+	Countdown:   {Running, Aborted},
+	Running:     {Paused, Finished, Aborted},
+	Paused:      {Running, Aborted},
+	Finished:    {},
+	Aborted:     {},
+}
+
+// Phases returns all phases in the order a game goes through them
+func Phases() []Phase {
+	return []Phase{Registering, Countdown, Running, Paused, Finished, Aborted}
+}
+
+// CanTransition reports whether the game may move from one phase to another
+func CanTransition(from, to Phase) bool {
+	for _, phase := range transitions[from] {
+		if phase == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Terminal reports whether the game is over in this phase
+func (p Phase) Terminal() bool {
+	next, ok := transitions[p]
+
+	return ok && len(next) == 0
+}
+
+// Init stores the Registering phase unless a phase is already stored, e.g. by a controller before restarting
+func Init(ctx context.Context, db datastore.Datastore) error {
+	err := db.Transaction(ctx).
+		If(datastore.CompareAbsent(Key)).
+		Then(datastore.OpPut(Key, string(Registering)), datastore.OpPut(SinceKey, formatTime(time.Now()))).
+		Commit()
+	if err != nil && !errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return fmt.Errorf("init game phase: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the stored game phase, Registering if none is stored yet
+func Get(ctx context.Context, db datastore.Datastore) (Phase, error) {
+	phase, err := db.Get(ctx, Key)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return Registering, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("get game phase: %w", err)
+	}
+
+	return Phase(phase), nil
+}
+
+// Since returns when the stored game phase was entered
+func Since(ctx context.Context, db datastore.Datastore) (time.Time, error) {
+	since, err := db.Get(ctx, SinceKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get game phase time: %w", err)
+	}
+
+	return time.Parse(time.RFC3339Nano, since)
+}
+
+// Transition moves the game from one phase to another in a transaction, so that it only succeeds if the game is still
+// in the from phase. Returns ErrInvalidTransition if the phases may not follow each other and ErrPhaseChanged if the
+// game isn't in the from phase anymore
+func Transition(ctx context.Context, db datastore.Datastore, from, to Phase) error {
+	return TransitionIf(ctx, db, from, to, nil)
+}
+
+// Pause freezes a running game
+func Pause(ctx context.Context, db datastore.Datastore) error {
+	return Transition(ctx, db, Running, Paused)
+}
+
+// Abort ends the game from whichever phase it's in and returns that phase
+func Abort(ctx context.Context, db datastore.Datastore) (Phase, error) {
+	phase, err := Get(ctx, db)
+	if err != nil {
+		return "", err
+	}
+
+	return phase, Transition(ctx, db, phase, Aborted)
+}
+
+// Restart replaces a game that's over with a new one in the Registering phase, if cmps hold as well, applying ops with
+// it. Returns ErrInvalidTransition if the game isn't over and ErrPhaseChanged if it isn't in the from phase anymore or
+// any of cmps doesn't hold
+func Restart(ctx context.Context, db datastore.Datastore, from Phase, cmps []datastore.Cmp, ops ...datastore.Op) error {
+	if !from.Terminal() {
+		return fmt.Errorf("%w: %s to %s, the game isn't over", ErrInvalidTransition, from, Registering)
+	}
+
+	err := db.Transaction(ctx).
+		If(append([]datastore.Cmp{datastore.Compare(Key, "=", string(from))}, cmps...)...).
+		Then(append([]datastore.Op{
+			datastore.OpPut(Key, string(Registering)),
+			datastore.OpPut(SinceKey, formatTime(time.Now())),
+			datastore.OpDelete(PausedKey),
+		}, ops...)...).
+		Commit()
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return fmt.Errorf("%w: game isn't %s anymore", ErrPhaseChanged, from)
+	}
+
+	if err != nil {
+		return fmt.Errorf("restart game from %s: %w", from, err)
+	}
+
+	return nil
+}
+
+// Resume continues a paused game and adds the pause to the paused time
+func Resume(ctx context.Context, db datastore.Datastore) error {
+	since, err := db.Get(ctx, SinceKey)
+	if err != nil {
+		return fmt.Errorf("get pause start: %w", err)
+	}
+
+	pausedAt, err := time.Parse(time.RFC3339Nano, since)
+	if err != nil {
+		return fmt.Errorf("parse pause start: %w", err)
+	}
+
+	paused, err := pausedBefore(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	// the pause start is compared too, so that a pause is only added once
+	return TransitionIf(ctx, db, Paused, Running,
+		[]datastore.Cmp{datastore.Compare(SinceKey, "=", since)},
+		datastore.OpPut(PausedKey, (paused+time.Since(pausedAt)).String()),
+	)
+}
+
+// PausedTime returns for how long the game was paused, including the current pause
+func PausedTime(ctx context.Context, db datastore.Datastore) (time.Duration, error) {
+	paused, err := pausedBefore(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	phase, err := Get(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	if phase != Paused {
+		return paused, nil
+	}
+
+	pausedAt, err := Since(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	return paused + time.Since(pausedAt), nil
+}
+
+// pausedBefore returns for how long the game was paused before the current pause
+func pausedBefore(ctx context.Context, db datastore.Datastore) (time.Duration, error) {
+	paused, err := db.Get(ctx, PausedKey)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("get paused time: %w", err)
+	}
+
+	return time.ParseDuration(paused)
+}
+
+// TransitionIf is Transition which also requires cmps to hold and applies ops with the transition, it returns
+// ErrPhaseChanged if any of cmps doesn't hold
+func TransitionIf(ctx context.Context, db datastore.Datastore, from, to Phase, cmps []datastore.Cmp, ops ...datastore.Op) error {
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	err := db.Transaction(ctx).
+		If(append([]datastore.Cmp{datastore.Compare(Key, "=", string(from))}, cmps...)...).
+		Then(append([]datastore.Op{datastore.OpPut(Key, string(to)), datastore.OpPut(SinceKey, formatTime(time.Now()))}, ops...)...).
+		Commit()
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return fmt.Errorf("%w: game isn't %s anymore", ErrPhaseChanged, from)
+	}
+
+	if err != nil {
+		return fmt.Errorf("transition game phase from %s to %s: %w", from, to, err)
+	}
+
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
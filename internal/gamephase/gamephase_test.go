@@ -0,0 +1,199 @@
+package gamephase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name      string
+		stored    gamephase.Phase
+		from      gamephase.Phase
+		to        gamephase.Phase
+		wantErr   error
+		wantPhase gamephase.Phase
+	}{
+		{"start countdown", gamephase.Registering, gamephase.Registering, gamephase.Countdown, nil, gamephase.Countdown},
+		{"start running", gamephase.Countdown, gamephase.Countdown, gamephase.Running, nil, gamephase.Running},
+		{"pause", gamephase.Running, gamephase.Running, gamephase.Paused, nil, gamephase.Paused},
+		{"resume", gamephase.Paused, gamephase.Paused, gamephase.Running, nil, gamephase.Running},
+		{"finish", gamephase.Running, gamephase.Running, gamephase.Finished, nil, gamephase.Finished},
+		{"abort countdown", gamephase.Countdown, gamephase.Countdown, gamephase.Aborted, nil, gamephase.Aborted},
+		{"skip countdown", gamephase.Registering, gamephase.Registering, gamephase.Running, gamephase.ErrInvalidTransition, gamephase.Registering},
+		{"finish while paused", gamephase.Paused, gamephase.Paused, gamephase.Finished, gamephase.ErrInvalidTransition, gamephase.Paused},
+		{"restart finished game", gamephase.Finished, gamephase.Finished, gamephase.Running, gamephase.ErrInvalidTransition, gamephase.Finished},
+		{"phase changed concurrently", gamephase.Aborted, gamephase.Running, gamephase.Paused, gamephase.ErrPhaseChanged, gamephase.Aborted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.stored)))
+
+			// execute
+			err := gamephase.Transition(ctx, fakeDatastore, tc.from, tc.to)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			phase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantPhase, phase)
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+
+	// execute, a restarted controller mustn't reset the phase
+	assert.NoError(t, gamephase.Init(ctx, fakeDatastore))
+	assert.NoError(t, gamephase.Transition(ctx, fakeDatastore, gamephase.Registering, gamephase.Countdown))
+	assert.NoError(t, gamephase.Init(ctx, fakeDatastore))
+
+	// verify
+	phase, err := gamephase.Get(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Countdown, phase)
+
+	_, err = gamephase.Since(ctx, fakeDatastore)
+	assert.NoError(t, err)
+}
+
+func TestTerminal(t *testing.T) {
+	for _, phase := range gamephase.Phases() {
+		want := phase == gamephase.Finished || phase == gamephase.Aborted
+		assert.Equal(t, want, phase.Terminal(), phase)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+	// execute, pause twice
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, gamephase.Pause(ctx, fakeDatastore))
+		assert.ErrorIs(t, gamephase.Pause(ctx, fakeDatastore), gamephase.ErrPhaseChanged)
+
+		time.Sleep(20 * time.Millisecond)
+
+		paused, err := gamephase.PausedTime(ctx, fakeDatastore)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, paused, time.Duration(i+1)*20*time.Millisecond)
+
+		assert.NoError(t, gamephase.Resume(ctx, fakeDatastore))
+		assert.ErrorIs(t, gamephase.Resume(ctx, fakeDatastore), gamephase.ErrPhaseChanged)
+	}
+
+	// verify, only the pauses count
+	time.Sleep(100 * time.Millisecond)
+
+	paused, err := gamephase.PausedTime(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, paused, 40*time.Millisecond)
+	assert.Less(t, paused, 100*time.Millisecond)
+
+	phase, err := gamephase.Get(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Running, phase)
+}
+
+func TestAbort(t *testing.T) {
+	tests := []struct {
+		stored  gamephase.Phase
+		wantErr error
+	}{
+		{gamephase.Registering, nil},
+		{gamephase.Countdown, nil},
+		{gamephase.Running, nil},
+		{gamephase.Paused, nil},
+		{gamephase.Finished, gamephase.ErrInvalidTransition},
+		{gamephase.Aborted, gamephase.ErrInvalidTransition},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.stored), func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.stored)))
+
+			// execute
+			from, err := gamephase.Abort(ctx, fakeDatastore)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.stored, from)
+
+			phase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+
+			if tc.wantErr == nil {
+				assert.Equal(t, gamephase.Aborted, phase)
+			} else {
+				assert.Equal(t, tc.stored, phase)
+			}
+		})
+	}
+}
+
+func TestRestart(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  gamephase.Phase
+		from    gamephase.Phase
+		wantErr error
+	}{
+		{"finished", gamephase.Finished, gamephase.Finished, nil},
+		{"aborted", gamephase.Aborted, gamephase.Aborted, nil},
+		{"running", gamephase.Running, gamephase.Running, gamephase.ErrInvalidTransition},
+		{"changed", gamephase.Aborted, gamephase.Finished, gamephase.ErrPhaseChanged},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.stored)))
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.PausedKey, "1s"))
+
+			// execute
+			err := gamephase.Restart(ctx, fakeDatastore, tc.from, nil, datastore.OpPut("round", "2"))
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			phase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+
+			paused, err := gamephase.PausedTime(ctx, fakeDatastore)
+			assert.NoError(t, err)
+
+			_, roundErr := fakeDatastore.Get(ctx, "round")
+
+			if tc.wantErr == nil {
+				assert.Equal(t, gamephase.Registering, phase)
+				assert.Zero(t, paused)
+				assert.NoError(t, roundErr)
+			} else {
+				assert.Equal(t, tc.stored, phase)
+				assert.Equal(t, time.Second, paused)
+				assert.Error(t, roundErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package gamephase
+
+import (
+	"context"
+	"sync"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// PhaseMetric is 1 for the current game phase and 0 for the others
+const PhaseMetric = "game_phase"
+
+// Watcher polls the game phase, so that components can cheaply gate their behaviour on it and wait for it to change.
+// A nil Watcher always reports Running
+type Watcher struct {
+	logger       *zap.Logger
+	db           datastore.Datastore
+	pollInterval time.Duration
+	metrics      *metrics.Registry
+
+	mu    *sync.Mutex
+	phase Phase
+	// changed is closed and replaced on every phase change
+	changed chan struct{}
+}
+
+func NewWatcher(logger *zap.Logger, db datastore.Datastore, pollInterval time.Duration, registry *metrics.Registry) *Watcher {
+	return &Watcher{
+		logger:       logger,
+		db:           db,
+		pollInterval: pollInterval,
+		metrics:      registry,
+		mu:           &sync.Mutex{},
+		phase:        Registering,
+		changed:      make(chan struct{}),
+	}
+}
+
+// Run polls the game phase until ctx is done, call is blocking
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	phase, err := Get(ctx, w.db)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Warn("poll game phase", zap.Error(err))
+		}
+
+		return
+	}
+
+	w.set(phase)
+}
+
+// set stores the phase and lets the waiters and observers know if it changed
+func (w *Watcher) set(phase Phase) {
+	w.mu.Lock()
+
+	from := w.phase
+	if phase == from {
+		w.mu.Unlock()
+		return
+	}
+
+	w.phase = phase
+	close(w.changed)
+	w.changed = make(chan struct{})
+
+	w.mu.Unlock()
+
+	w.logger.Info("game phase changed", zap.String("from", string(from)), zap.String("to", string(phase)))
+
+	if w.metrics != nil {
+		for _, p := range Phases() {
+			value := 0.0
+			if p == phase {
+				value = 1
+			}
+
+			w.metrics.SetGauge(PhaseMetric, value, "phase", string(p))
+		}
+	}
+}
+
+// Phase returns the latest known game phase
+func (w *Watcher) Phase() Phase {
+	if w == nil {
+		return Running
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.phase
+}
+
+// Wait blocks until the game is in one of the phases and returns the phase, or returns ctx's error once it's done
+func (w *Watcher) Wait(ctx context.Context, phases ...Phase) (Phase, error) {
+	if w == nil {
+		return Running, nil
+	}
+
+	for {
+		w.mu.Lock()
+		phase, changed := w.phase, w.changed
+		w.mu.Unlock()
+
+		for _, p := range phases {
+			if p == phase {
+				return phase, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return phase, ctx.Err()
+		case <-changed:
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gamephase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestWatcher(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, gamephase.Init(ctx, fakeDatastore))
+
+	registry := metrics.New()
+	watcher := gamephase.NewWatcher(zap.NewNop(), fakeDatastore, time.Millisecond, registry)
+
+	go watcher.Run(ctx)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, gamephase.Transition(ctx, fakeDatastore, gamephase.Registering, gamephase.Countdown))
+		assert.NoError(t, gamephase.Transition(ctx, fakeDatastore, gamephase.Countdown, gamephase.Aborted))
+	}()
+
+	// execute
+	phase, err := watcher.Wait(ctx, gamephase.Running, gamephase.Aborted)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Aborted, phase)
+	assert.Equal(t, gamephase.Aborted, watcher.Phase())
+	assert.Equal(t, 1.0, registry.Gauge(gamephase.PhaseMetric, "phase", string(gamephase.Aborted)))
+	assert.Equal(t, 0.0, registry.Gauge(gamephase.PhaseMetric, "phase", string(gamephase.Countdown)))
+}
+
+func TestWatcherWaitCanceled(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	watcher := gamephase.NewWatcher(zap.NewNop(), datastore.NewFakeClient(), time.Millisecond, nil)
+
+	// execute
+	phase, err := watcher.Wait(ctx, gamephase.Running)
+
+	// verify
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, gamephase.Registering, phase)
+}
+
+func TestNilWatcher(t *testing.T) {
+	var watcher *gamephase.Watcher
+
+	phase, err := watcher.Wait(context.Background(), gamephase.Running)
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Running, phase)
+	assert.Equal(t, gamephase.Running, watcher.Phase())
+}
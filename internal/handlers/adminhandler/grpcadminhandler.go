@@ -0,0 +1,424 @@
+package adminhandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"wildwest/internal/barrier"
+	"wildwest/internal/broadcastdispatcher"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/intervention"
+	"wildwest/internal/schedule"
+	"wildwest/internal/series"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/tlsconfig"
+	"wildwest/internal/utils"
+	"wildwest/internal/winner"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	adminpb "wildwest/api/proto/admin"
+)
+
+type GRPCAdminHandler struct {
+	adminpb.UnimplementedAdminServiceServer
+	logger     *zap.Logger
+	db         datastore.Datastore
+	roster     []utils.Cowboy
+	gameMaster *intervention.GameMaster
+
+	mu           *sync.Mutex
+	startRequest *broadcastdispatcher.StartRequest
+}
+
+// NewGRPC creates an admin handler for the game of the cowboys in roster, startRequest starts the shootout of the first
+// game and gameMaster applies the interventions
+func NewGRPC(logger *zap.Logger, db datastore.Datastore, roster []utils.Cowboy, startRequest *broadcastdispatcher.StartRequest, gameMaster *intervention.GameMaster) *GRPCAdminHandler {
+	return &GRPCAdminHandler{
+		logger:       logger,
+		db:           db,
+		roster:       roster,
+		gameMaster:   gameMaster,
+		mu:           &sync.Mutex{},
+		startRequest: startRequest,
+	}
+}
+
+// SetStartRequest replaces the start request of the game that's over with the one of the next game, so that a start
+// requested in one game doesn't start the next one too
+func (ah *GRPCAdminHandler) SetStartRequest(startRequest *broadcastdispatcher.StartRequest) {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+
+	ah.startRequest = startRequest
+}
+
+// currentStartRequest returns the start request of the game being played
+func (ah *GRPCAdminHandler) currentStartRequest() *broadcastdispatcher.StartRequest {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+
+	return ah.startRequest
+}
+
+// GetGameStatus returns the game id and phase, the health, readiness and phase of every cowboy in the roster and the
+// standings of the series
+func (ah *GRPCAdminHandler) GetGameStatus(ctx context.Context, _ *adminpb.GetGameStatusRequest) (*adminpb.GetGameStatusResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	phase, err := gamephase.Get(ctx, ah.db)
+	if err != nil {
+		return nil, err
+	}
+
+	paused, err := gamephase.PausedTime(ctx, ah.db)
+	if err != nil {
+		return nil, err
+	}
+
+	healths, err := getByID(ctx, ah.db, utils.CowboyKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	phases, err := getByID(ctx, ah.db, shootoutstarter.PhaseKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ready, err := barrier.Ready(ctx, ah.db, len(ah.roster))
+	if err != nil {
+		return nil, err
+	}
+
+	isReady := make(map[int]bool, len(ready))
+	for _, id := range ready {
+		isReady[id] = true
+	}
+
+	resp := &adminpb.GetGameStatusResponse{
+		GamePhase:  string(phase),
+		PausedTime: durationpb.New(paused),
+		Cowboys:    make([]*adminpb.CowboyStatus, 0, len(ah.roster)),
+	}
+
+	for id, cowboy := range ah.roster {
+		cowboyStatus := &adminpb.CowboyStatus{
+			Id:    int32(id),
+			Name:  cowboy.Name,
+			Ready: isReady[id],
+			Phase: phases[id],
+		}
+
+		if health, ok := healths[id]; ok {
+			cowboyStatus.Joined = true
+
+			cowboyStatus.Health, err = strconv.ParseInt(health, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse health of cowboy %d: %w", id, err)
+			}
+
+			if cowboyStatus.Health > 0 {
+				resp.Alive++
+			} else {
+				resp.Dead++
+			}
+		}
+
+		resp.Cowboys = append(resp.Cowboys, cowboyStatus)
+	}
+
+	record, err := winner.Get(ctx, ah.db)
+	switch {
+	case errors.Is(err, winner.ErrNoWinner):
+	case err != nil:
+		return nil, err
+	default:
+		resp.Winner = &adminpb.Winner{
+			Id:         int32(record.ID),
+			Name:       record.Name,
+			Health:     int64(record.Health),
+			DeclaredAt: timestamppb.New(record.DeclaredAt),
+		}
+	}
+
+	game, err := schedule.Current(ctx, ah.db)
+	switch {
+	case errors.Is(err, schedule.ErrNoGame):
+	case err != nil:
+		return nil, err
+	default:
+		resp.GameId = game.ID
+	}
+
+	round, err := series.CurrentRound(ctx, ah.db)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Round = int32(round)
+
+	results, err := series.RoundResults(ctx, ah.db, round)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, standing := range series.Standings(ah.roster, results) {
+		resp.Standings = append(resp.Standings, &adminpb.Standing{
+			Id:     int32(standing.ID),
+			Name:   standing.Name,
+			Points: int32(standing.Points),
+			Wins:   int32(standing.Wins),
+			Kills:  int32(standing.Kills),
+		})
+	}
+
+	return resp, nil
+}
+
+// ListCowboys returns the roster
+func (ah *GRPCAdminHandler) ListCowboys(ctx context.Context, _ *adminpb.ListCowboysRequest) (*adminpb.ListCowboysResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	resp := &adminpb.ListCowboysResponse{
+		Cowboys: make([]*adminpb.Cowboy, 0, len(ah.roster)),
+	}
+
+	for id, cowboy := range ah.roster {
+		resp.Cowboys = append(resp.Cowboys, &adminpb.Cowboy{
+			Id:     int32(id),
+			Name:   cowboy.Name,
+			Health: cowboy.Health,
+			Damage: cowboy.Damage,
+		})
+	}
+
+	return resp, nil
+}
+
+// StartNow starts the shootout with the ready cowboys, it's only possible while they're registering
+func (ah *GRPCAdminHandler) StartNow(ctx context.Context, _ *adminpb.StartNowRequest) (*adminpb.StartNowResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	phase, err := gamephase.Get(ctx, ah.db)
+	if err != nil {
+		return nil, err
+	}
+
+	if phase != gamephase.Registering {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v: game is %s", gamephase.ErrInvalidTransition, phase)
+	}
+
+	ready, err := barrier.Ready(ctx, ah.db, len(ah.roster))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ready) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, broadcastdispatcher.ErrNoCowboysReady.Error())
+	}
+
+	ah.currentStartRequest().Request()
+	ah.logger.Info("shootout start requested", zap.Ints("ready", ready))
+
+	resp := &adminpb.StartNowResponse{
+		Ready: make([]int32, 0, len(ready)),
+	}
+
+	for _, id := range ready {
+		resp.Ready = append(resp.Ready, int32(id))
+	}
+
+	return resp, nil
+}
+
+// Abort aborts the game
+func (ah *GRPCAdminHandler) Abort(ctx context.Context, req *adminpb.AbortRequest) (*adminpb.AbortResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	from, err := gamephase.Abort(ctx, ah.db)
+	if err != nil {
+		return nil, phaseError(err)
+	}
+
+	ah.logger.Warn("game aborted", zap.String("from", string(from)), zap.String("reason", req.GetReason()))
+
+	return &adminpb.AbortResponse{
+		PreviousGamePhase: string(from),
+		GamePhase:         string(gamephase.Aborted),
+	}, nil
+}
+
+// Pause pauses the running game
+func (ah *GRPCAdminHandler) Pause(ctx context.Context, _ *adminpb.PauseRequest) (*adminpb.PauseResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if err := gamephase.Pause(ctx, ah.db); err != nil {
+		return nil, phaseError(err)
+	}
+
+	paused, err := gamephase.PausedTime(ctx, ah.db)
+	if err != nil {
+		return nil, err
+	}
+
+	ah.logger.Info("game paused")
+
+	return &adminpb.PauseResponse{
+		GamePhase:  string(gamephase.Paused),
+		PausedTime: durationpb.New(paused),
+	}, nil
+}
+
+// Resume resumes the paused game
+func (ah *GRPCAdminHandler) Resume(ctx context.Context, _ *adminpb.ResumeRequest) (*adminpb.ResumeResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if err := gamephase.Resume(ctx, ah.db); err != nil {
+		return nil, phaseError(err)
+	}
+
+	paused, err := gamephase.PausedTime(ctx, ah.db)
+	if err != nil {
+		return nil, err
+	}
+
+	ah.logger.Info("game resumed", zap.Duration("paused_time", paused))
+
+	return &adminpb.ResumeResponse{
+		GamePhase:  string(gamephase.Running),
+		PausedTime: durationpb.New(paused),
+	}, nil
+}
+
+// SetHealth sets the health of an alive cowboy
+func (ah *GRPCAdminHandler) SetHealth(ctx context.Context, req *adminpb.SetHealthRequest) (*adminpb.InterventionResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	result, err := ah.gameMaster.SetHealth(ctx, int(req.GetId()), int(req.GetHealth()), audit(ctx, req.GetReason()))
+
+	return interventionResponse(req.GetId(), result, err)
+}
+
+// Revive brings a dead cowboy back into the game
+func (ah *GRPCAdminHandler) Revive(ctx context.Context, req *adminpb.ReviveRequest) (*adminpb.InterventionResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	result, err := ah.gameMaster.Revive(ctx, int(req.GetId()), int(req.GetHealth()), audit(ctx, req.GetReason()))
+
+	return interventionResponse(req.GetId(), result, err)
+}
+
+// Kick removes an alive cowboy from the game
+func (ah *GRPCAdminHandler) Kick(ctx context.Context, req *adminpb.KickRequest) (*adminpb.InterventionResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	result, err := ah.gameMaster.Kick(ctx, int(req.GetId()), audit(ctx, req.GetReason()))
+
+	return interventionResponse(req.GetId(), result, err)
+}
+
+// Smite deals damage to an alive cowboy
+func (ah *GRPCAdminHandler) Smite(ctx context.Context, req *adminpb.SmiteRequest) (*adminpb.InterventionResponse, error) {
+	if err := tlsconfig.AuthorizeAdmin(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	result, err := ah.gameMaster.Smite(ctx, int(req.GetId()), int(req.GetDamage()), audit(ctx, req.GetReason()))
+
+	return interventionResponse(req.GetId(), result, err)
+}
+
+// audit describes the caller of an intervention, callers without mTLS are unknown
+func audit(ctx context.Context, reason string) intervention.Audit {
+	actor, ok := tlsconfig.PeerID(ctx)
+	if !ok {
+		actor = "unknown"
+	}
+
+	return intervention.Audit{Actor: actor, Reason: reason}
+}
+
+// interventionResponse converts the result of an intervention, its errors into gRPC status errors
+func interventionResponse(id int32, result intervention.Result, err error) (*adminpb.InterventionResponse, error) {
+	switch {
+	case errors.Is(err, intervention.ErrInvalidValue):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, intervention.ErrNotJoined):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, intervention.ErrChanged):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, intervention.ErrGameOver), errors.Is(err, intervention.ErrGameNotActive),
+		errors.Is(err, intervention.ErrCowboyDead), errors.Is(err, intervention.ErrCowboyAlive):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &adminpb.InterventionResponse{
+		Id:             id,
+		PreviousHealth: int64(result.PreviousHealth),
+		Health:         int64(result.Health),
+	}, nil
+}
+
+// phaseError converts rejected game phase transitions into gRPC status errors
+func phaseError(err error) error {
+	switch {
+	case errors.Is(err, gamephase.ErrInvalidTransition), errors.Is(err, gamephase.ErrPhaseChanged):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+// getByID returns the values of the keys with the prefix by the cowboy id following it
+func getByID(ctx context.Context, db datastore.Datastore, prefix string) (map[int]string, error) {
+	kvs, err := db.GetPrefix(ctx, prefix)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get %s keys: %w", prefix, err)
+	}
+
+	values := make(map[int]string, len(kvs))
+
+	for key, value := range kvs {
+		id, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			continue
+		}
+
+		values[id] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,197 @@
+package adminhandler_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+	"wildwest/internal/barrier"
+	"wildwest/internal/broadcastdispatcher"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/handlers/adminhandler"
+	"wildwest/internal/schedule"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "wildwest/api/proto/admin"
+)
+
+var roster = []utils.Cowboy{
+	{Name: "John", Health: 10, Damage: 1},
+	{Name: "Bill", Health: 8, Damage: 2},
+	{Name: "Sam", Health: 5, Damage: 3},
+}
+
+func TestGetGameStatus(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "7"))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "0"))
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(0), "shooting"))
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(1), "dead"))
+	assert.NoError(t, barrier.Register(ctx, fakeDatastore, 0))
+	assert.NoError(t, barrier.Register(ctx, fakeDatastore, 1))
+
+	handler := adminhandler.NewGRPC(zap.NewNop(), fakeDatastore, roster, broadcastdispatcher.NewStartRequest(), nil)
+
+	// execute
+	resp, err := handler.GetGameStatus(ctx, &adminpb.GetGameStatusRequest{})
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, string(gamephase.Running), resp.GetGamePhase())
+	assert.Equal(t, int32(1), resp.GetAlive())
+	assert.Equal(t, int32(1), resp.GetDead())
+	assert.Nil(t, resp.GetWinner())
+	assert.Equal(t, int32(1), resp.GetRound())
+	assert.Empty(t, resp.GetStandings())
+	assert.Empty(t, resp.GetGameId())
+
+	want := []*adminpb.CowboyStatus{
+		{Id: 0, Name: "John", Health: 7, Joined: true, Ready: true, Phase: "shooting"},
+		{Id: 1, Name: "Bill", Health: 0, Joined: true, Ready: true, Phase: "dead"},
+		{Id: 2, Name: "Sam"},
+	}
+
+	for i, cowboy := range resp.GetCowboys() {
+		assert.Equal(t, want[i].String(), cowboy.String())
+	}
+}
+
+func TestStartNow(t *testing.T) {
+	tests := []struct {
+		name          string
+		phase         gamephase.Phase
+		readyIDs      []int
+		wantCode      codes.Code
+		wantRequested bool
+	}{
+		{"start with ready cowboys", gamephase.Registering, []int{0, 2}, codes.OK, true},
+		{"no cowboys ready", gamephase.Registering, nil, codes.FailedPrecondition, false},
+		{"already started", gamephase.Running, []int{0, 1, 2}, codes.FailedPrecondition, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.phase)))
+
+			for _, id := range tc.readyIDs {
+				assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+			}
+
+			startRequest := broadcastdispatcher.NewStartRequest()
+			handler := adminhandler.NewGRPC(zap.NewNop(), fakeDatastore, roster, startRequest, nil)
+
+			// execute
+			_, err := handler.StartNow(ctx, &adminpb.StartNowRequest{})
+
+			// verify
+			assert.Equal(t, tc.wantCode, status.Code(err))
+
+			requested := false
+			select {
+			case <-startRequest.Requested():
+				requested = true
+			default:
+			}
+
+			assert.Equal(t, tc.wantRequested, requested)
+		})
+	}
+}
+
+func TestAbort(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Paused)))
+
+	handler := adminhandler.NewGRPC(zap.NewNop(), fakeDatastore, roster, broadcastdispatcher.NewStartRequest(), nil)
+
+	// execute
+	resp, err := handler.Abort(ctx, &adminpb.AbortRequest{Reason: "testing"})
+	_, againErr := handler.Abort(ctx, &adminpb.AbortRequest{Reason: "testing"})
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, string(gamephase.Paused), resp.GetPreviousGamePhase())
+	assert.Equal(t, codes.FailedPrecondition, status.Code(againErr))
+}
+
+func TestStartNowScheduledGames(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Registering)))
+
+	handler := adminhandler.NewGRPC(zap.NewNop(), fakeDatastore, roster[:2], broadcastdispatcher.NewStartRequest(), nil)
+
+	// play registers the cowboys and plays a game scheduled at the given time with its own start request
+	play := func(scheduledAt time.Time) (*broadcastdispatcher.StartRequest, error) {
+		for id := 0; id < 2; id++ {
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), "10"))
+			assert.NoError(t, barrier.Register(ctx, fakeDatastore, id))
+
+			go shootoutstarter.NewDatastore(zap.NewNop(), id, fakeDatastore, 10*time.Millisecond).WaitForShootout(ctx) //nolint:errcheck
+		}
+
+		startRequest := broadcastdispatcher.NewStartRequest()
+		handler.SetStartRequest(startRequest)
+
+		return startRequest, broadcastdispatcher.BroadcastShootoutTime(ctx, zap.NewNop(), &broadcastdispatcher.Config{
+			Replicas:               2,
+			DB:                     fakeDatastore,
+			ReadinessTimeout:       time.Second,
+			ReadinessTimeoutPolicy: utils.ReadinessTimeoutPolicyAbort,
+			StartRequest:           startRequest,
+			StartMode:              utils.StartModePull,
+			Countdown:              100 * time.Millisecond,
+			StartAt:                scheduledAt,
+		})
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		_, err := handler.StartNow(ctx, &adminpb.StartNowRequest{})
+		assert.NoError(t, err)
+	}()
+
+	// execute, the first game is started an hour early and the next one is played as scheduled
+	_, err := play(time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	assert.NoError(t, gamephase.Transition(ctx, fakeDatastore, gamephase.Running, gamephase.Finished))
+
+	scheduledAt := time.Now().Add(300 * time.Millisecond)
+	assert.NoError(t, schedule.Next(ctx, fakeDatastore, gamephase.Finished, roster[:2], schedule.NewGame("wildwest", 1, scheduledAt)))
+
+	startRequest, err := play(scheduledAt)
+
+	// verify
+	assert.NoError(t, err)
+
+	select {
+	case <-startRequest.Requested():
+		t.Error("start of the next game requested")
+	default:
+	}
+
+	startAt, err := fakeDatastore.Get(ctx, shootoutstarter.StartAtKey)
+	assert.NoError(t, err)
+	assert.Equal(t, shootoutstarter.FormatStartAt(scheduledAt), startAt)
+}
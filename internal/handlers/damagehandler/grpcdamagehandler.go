@@ -2,9 +2,18 @@ package damagehandler
 
 import (
 	"context"
+	"errors"
+	"io"
 	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/shotauth"
+	"wildwest/internal/tlsconfig"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	damagepb "wildwest/api/proto/damage"
@@ -12,18 +21,86 @@ import (
 
 type GRPCDamageHandler struct {
 	damagepb.UnimplementedDamageServiceServer
-	logger        *zap.Logger
-	damageApplier damageapplier.DamageApplier
+	logger           *zap.Logger
+	damageApplier    damageapplier.DamageApplier
+	verifier         *shotauth.Verifier
+	shotInterceptors []grpc.UnaryServerInterceptor
 }
 
-func NewGRPC(logger *zap.Logger, damageApplier damageapplier.DamageApplier) *GRPCDamageHandler {
+// NewGRPC creates the damage handler, shotInterceptors are applied to every shot received over a stream like to unary calls
+func NewGRPC(logger *zap.Logger, damageApplier damageapplier.DamageApplier, verifier *shotauth.Verifier, shotInterceptors []grpc.UnaryServerInterceptor) *GRPCDamageHandler {
 	return &GRPCDamageHandler{
-		logger:        logger,
-		damageApplier: damageApplier,
+		logger:           logger,
+		damageApplier:    damageApplier,
+		verifier:         verifier,
+		shotInterceptors: shotInterceptors,
 	}
 }
 
 func (dh *GRPCDamageHandler) ReceiveDamage(ctx context.Context, req *damagepb.DamageRequest) (*emptypb.Empty, error) {
+	// make sure the shooter is who it claims to be
+	if err := tlsconfig.AuthorizeCowboy(ctx, int(req.GetFrom())); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	// make sure the shot was signed by the shooter and isn't replayed
+	if err := dh.verifier.Verify(req.GetFrom(), req.GetDamage(), shotauth.Signature{
+		Nonce:     req.GetNonce(),
+		SentAt:    req.GetSentAt(),
+		Signature: req.GetSignature(),
+	}); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
 	_, err := dh.damageApplier.ApplyDamage(ctx, int(req.GetFrom()), int(req.GetDamage()))
-	return &emptypb.Empty{}, err
+	return &emptypb.Empty{}, damageError(err)
+}
+
+// StreamDamage applies shots received over the stream in order and acknowledges every shot with its sequence number
+func (dh *GRPCDamageHandler) StreamDamage(stream damagepb.DamageService_StreamDamageServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = interceptors.ServerInvoke(stream.Context(), dh.shotInterceptors, damagepb.DamageService_ReceiveDamage_FullMethodName, req,
+			func(ctx context.Context, req any) (any, error) {
+				return dh.ReceiveDamage(ctx, req.(*damagepb.DamageRequest))
+			},
+		)
+
+		st := status.Convert(err)
+
+		if err := stream.Send(&damagepb.DamageAck{
+			Seq:     req.GetSeq(),
+			Code:    int32(st.Code()),
+			Message: st.Message(),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// damageError converts shots rejected by the damage applier into gRPC status errors
+func damageError(err error) error {
+	switch {
+	case errors.Is(err, damageapplier.ErrFireRateExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, damageapplier.ErrUnknownShooter),
+		errors.Is(err, damageapplier.ErrDamageNotPositive),
+		errors.Is(err, damageapplier.ErrDamageMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, damageapplier.ErrGameNotRunning):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, datastore.ErrTransactionUnsuccessful):
+		// the shooter is dead or the target's health changed meanwhile, the shooter may retry if it's alive
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return err
+	}
 }
@@ -0,0 +1,120 @@
+package damagehandler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/shotauth"
+	"wildwest/internal/tlsconfig"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
+
+type GRPCDamageV2Handler struct {
+	damagev2pb.UnimplementedDamageServiceServer
+	logger           *zap.Logger
+	damageApplier    damageapplier.DamageApplier
+	verifier         *shotauth.Verifier
+	shotInterceptors []grpc.UnaryServerInterceptor
+}
+
+// NewGRPCV2 creates the v2 damage handler, shotInterceptors are applied to every shot received over a stream like to unary calls
+func NewGRPCV2(logger *zap.Logger, damageApplier damageapplier.DamageApplier, verifier *shotauth.Verifier, shotInterceptors []grpc.UnaryServerInterceptor) *GRPCDamageV2Handler {
+	return &GRPCDamageV2Handler{
+		logger:           logger,
+		damageApplier:    damageApplier,
+		verifier:         verifier,
+		shotInterceptors: shotInterceptors,
+	}
+}
+
+func (dh *GRPCDamageV2Handler) ReceiveDamage(ctx context.Context, req *damagev2pb.DamageRequest) (*damagev2pb.DamageResponse, error) {
+	// make sure the shooter is who it claims to be
+	if err := tlsconfig.AuthorizeCowboy(ctx, int(req.GetFrom())); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	// make sure the shot was signed by the shooter and isn't replayed
+	if err := dh.verifier.Verify(req.GetFrom(), req.GetDamage(), shotauth.Signature{
+		Nonce:     req.GetNonce(),
+		SentAt:    req.GetSentAt(),
+		Signature: req.GetSignature(),
+	}); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return ApplyDamage(ctx, dh.damageApplier, int(req.GetFrom()), int(req.GetDamage()))
+}
+
+// StreamDamage applies shots received over the stream in order and acknowledges every shot with its sequence number
+func (dh *GRPCDamageV2Handler) StreamDamage(stream damagev2pb.DamageService_StreamDamageServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		resp, err := interceptors.ServerInvoke(stream.Context(), dh.shotInterceptors, damagev2pb.DamageService_ReceiveDamage_FullMethodName, req,
+			func(ctx context.Context, req any) (any, error) {
+				return dh.ReceiveDamage(ctx, req.(*damagev2pb.DamageRequest))
+			},
+		)
+
+		st := status.Convert(err)
+
+		ack := &damagev2pb.DamageAck{
+			Seq:     req.GetSeq(),
+			Code:    int32(st.Code()),
+			Message: st.Message(),
+		}
+		if err == nil {
+			ack.Response = resp.(*damagev2pb.DamageResponse)
+		}
+
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
+// ApplyDamage applies the shot and describes its outcome for the shooter
+func ApplyDamage(ctx context.Context, damageApplier damageapplier.DamageApplier, from int, damage int) (*damagev2pb.DamageResponse, error) {
+	health, err := damageApplier.ApplyDamage(ctx, from, damage)
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		// the transaction fails if either we or the shooter are dead, only a dead shooter is an error
+		if currentHealth, healthErr := damageApplier.GetHealth(ctx); healthErr == nil && currentHealth <= 0 {
+			return &damagev2pb.DamageResponse{
+				Outcome:    damagev2pb.Outcome_OUTCOME_TARGET_DEAD,
+				TargetDead: true,
+			}, nil
+		}
+	}
+
+	if err != nil {
+		return nil, damageError(err)
+	}
+
+	if health <= 0 {
+		return &damagev2pb.DamageResponse{
+			Outcome:    damagev2pb.Outcome_OUTCOME_KILLED,
+			TargetDead: true,
+		}, nil
+	}
+
+	return &damagev2pb.DamageResponse{
+		Outcome:         damagev2pb.Outcome_OUTCOME_HIT,
+		RemainingHealth: int64(health),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package damagehandler
+
+import (
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/utils"
+
+	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
+
+const ErrUnexpectedRequest = utils.ConstError("unexpected request type")
+
+// Validators returns the request validators of the DamageService methods
+func Validators(replicas int) map[string]interceptors.Validator {
+	return map[string]interceptors.Validator{
+		damagepb.DamageService_ReceiveDamage_FullMethodName: func(req any) error {
+			damageRequest, ok := req.(*damagepb.DamageRequest)
+			if !ok {
+				return ErrUnexpectedRequest
+			}
+
+			return validateDamageRequest(damageRequest.GetFrom(), damageRequest.GetDamage(), replicas)
+		},
+		damagev2pb.DamageService_ReceiveDamage_FullMethodName: func(req any) error {
+			damageRequest, ok := req.(*damagev2pb.DamageRequest)
+			if !ok {
+				return ErrUnexpectedRequest
+			}
+
+			return validateDamageRequest(damageRequest.GetFrom(), damageRequest.GetDamage(), replicas)
+		},
+	}
+}
+
+// validateDamageRequest checks that the shooter is one of the cowboys and that the damage is positive
+func validateDamageRequest(from int64, damage int64, replicas int) error {
+	if from < 0 || from >= int64(replicas) {
+		return damageapplier.ErrUnknownShooter
+	}
+
+	if damage <= 0 {
+		return damageapplier.ErrDamageNotPositive
+	}
+
+	return nil
+}
+
+// SheddableMethods returns the DamageService methods which are rejected first when the cowboy is overloaded
+func SheddableMethods() []string {
+	return []string{
+		damagepb.DamageService_ReceiveDamage_FullMethodName,
+		damagev2pb.DamageService_ReceiveDamage_FullMethodName,
+	}
+}
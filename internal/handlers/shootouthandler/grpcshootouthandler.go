@@ -5,8 +5,12 @@ import (
 	"time"
 	shootoutpb "wildwest/api/proto/shootout"
 	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/tlsconfig"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type GRPCShootoutHandler struct {
@@ -21,7 +25,28 @@ func NewGRPC(shootoutManager shootoutstarter.ShootoutStarter) *GRPCShootoutHandl
 }
 
 // ReceiveShootoutTime receives the shootout beginning time and sends it to the held channel
-func (sh *GRPCShootoutHandler) ReceiveShootoutTime(_ context.Context, req *shootoutpb.ReceiveShootoutTimeRequest) (*emptypb.Empty, error) {
-	sh.shootoutManager.ReceiveShootoutTime(time.Unix(req.Timestamp, 0))
+func (sh *GRPCShootoutHandler) ReceiveShootoutTime(ctx context.Context, req *shootoutpb.ReceiveShootoutTimeRequest) (*emptypb.Empty, error) {
+	// only the controller may begin the shootout
+	if err := tlsconfig.AuthorizeController(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	startTime := time.Unix(req.GetTimestamp(), 0) //nolint:staticcheck // fallback for controllers without start_time
+	if req.GetStartTime() != nil {
+		startTime = req.GetStartTime().AsTime()
+	}
+
+	sh.shootoutManager.ReceiveShootoutTime(startTime, req.GetClockOffset().AsDuration())
 	return &emptypb.Empty{}, nil
 }
+
+// SyncClock answers a clock offset estimation with our receive and send times
+func (sh *GRPCShootoutHandler) SyncClock(_ context.Context, req *shootoutpb.SyncClockRequest) (*shootoutpb.SyncClockResponse, error) {
+	receivedAt := timestamppb.Now()
+
+	return &shootoutpb.SyncClockResponse{
+		RequestSentAt: req.GetSentAt(),
+		ReceivedAt:    receivedAt,
+		SentAt:        timestamppb.Now(),
+	}, nil
+}
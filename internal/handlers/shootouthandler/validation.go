@@ -0,0 +1,45 @@
+package shootouthandler
+
+import (
+	"fmt"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/utils"
+
+	shootoutpb "wildwest/api/proto/shootout"
+)
+
+const (
+	ErrTimestampNotPositive = utils.ConstError("timestamp must be positive")
+	ErrInvalidStartTime     = utils.ConstError("invalid start time")
+	ErrUnexpectedRequest    = utils.ConstError("unexpected request type")
+)
+
+// Validators returns the request validators of the ShootoutService methods
+func Validators() map[string]interceptors.Validator {
+	return map[string]interceptors.Validator{
+		shootoutpb.ShootoutService_ReceiveShootoutTime_FullMethodName: func(req any) error {
+			shootoutTimeRequest, ok := req.(*shootoutpb.ReceiveShootoutTimeRequest)
+			if !ok {
+				return ErrUnexpectedRequest
+			}
+
+			if startTime := shootoutTimeRequest.GetStartTime(); startTime != nil {
+				if err := startTime.CheckValid(); err != nil {
+					return fmt.Errorf("%w: %v", ErrInvalidStartTime, err)
+				}
+
+				if startTime.AsTime().UnixNano() <= 0 {
+					return ErrTimestampNotPositive
+				}
+
+				return nil
+			}
+
+			if shootoutTimeRequest.GetTimestamp() <= 0 { //nolint:staticcheck // fallback for controllers without start_time
+				return ErrTimestampNotPositive
+			}
+
+			return nil
+		},
+	}
+}
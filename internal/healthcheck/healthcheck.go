@@ -0,0 +1,104 @@
+package healthcheck
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type Phase string
+
+const (
+	PhaseWaiting  Phase = "waiting"
+	PhaseShooting Phase = "shooting"
+	PhaseDead     Phase = "dead"
+	PhaseWinner   Phase = "winner"
+)
+
+// PhaseServicePrefix prefixes the pseudo-service of every phase, e.g. wildwest.phase.shooting is SERVING while shooting
+const PhaseServicePrefix = "wildwest.phase."
+
+var phases = []Phase{PhaseWaiting, PhaseShooting, PhaseDead, PhaseWinner}
+
+// PhaseService returns the name of the pseudo-service of a phase
+func PhaseService(phase Phase) string {
+	return PhaseServicePrefix + string(phase)
+}
+
+// Reporter serves grpc.health.v1 with statuses following the game phase
+type Reporter struct {
+	server   *health.Server
+	services map[string][]Phase
+
+	mu    *sync.Mutex
+	phase Phase
+}
+
+// New creates a reporter in the waiting phase, every given service is SERVING only in its phases
+func New(services map[string][]Phase) *Reporter {
+	r := &Reporter{
+		server:   health.NewServer(),
+		services: services,
+		mu:       &sync.Mutex{},
+	}
+
+	r.SetPhase(PhaseWaiting)
+
+	return r
+}
+
+// Server returns the grpc.health.v1 server to register on gRPC servers
+func (r *Reporter) Server() healthpb.HealthServer {
+	return r.server
+}
+
+// SetPhase updates the statuses of all services to the phase, a nil reporter ignores the phase
+func (r *Reporter) SetPhase(phase Phase) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.phase = phase
+
+	for _, p := range phases {
+		r.server.SetServingStatus(PhaseService(p), servingStatus(p == phase))
+	}
+
+	for service, servingPhases := range r.services {
+		serving := false
+		for _, p := range servingPhases {
+			serving = serving || p == phase
+		}
+
+		r.server.SetServingStatus(service, servingStatus(serving))
+	}
+}
+
+// Shutdown sets every service to NOT_SERVING and ignores later phases, a nil reporter is ignored
+func (r *Reporter) Shutdown() {
+	if r == nil {
+		return
+	}
+
+	r.server.Shutdown()
+}
+
+// Phase returns the current phase
+func (r *Reporter) Phase() Phase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.phase
+}
+
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
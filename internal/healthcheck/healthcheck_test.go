@@ -0,0 +1,65 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+	"wildwest/internal/healthcheck"
+
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestReporter(t *testing.T) {
+	const damageService = "damagepb.DamageService"
+
+	tests := []struct {
+		phase       healthcheck.Phase
+		wantServing []string
+	}{
+		{healthcheck.PhaseWaiting, []string{"", healthcheck.PhaseService(healthcheck.PhaseWaiting), damageService}},
+		{healthcheck.PhaseShooting, []string{"", healthcheck.PhaseService(healthcheck.PhaseShooting), damageService}},
+		{healthcheck.PhaseDead, []string{"", healthcheck.PhaseService(healthcheck.PhaseDead)}},
+		{healthcheck.PhaseWinner, []string{"", healthcheck.PhaseService(healthcheck.PhaseWinner)}},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.phase), func(t *testing.T) {
+			reporter := healthcheck.New(map[string][]healthcheck.Phase{
+				damageService: {healthcheck.PhaseWaiting, healthcheck.PhaseShooting},
+			})
+			server := reporter.Server()
+
+			reporter.SetPhase(tc.phase)
+			assert.Equal(t, tc.phase, reporter.Phase())
+
+			services := []string{
+				"",
+				damageService,
+				healthcheck.PhaseService(healthcheck.PhaseWaiting),
+				healthcheck.PhaseService(healthcheck.PhaseShooting),
+				healthcheck.PhaseService(healthcheck.PhaseDead),
+				healthcheck.PhaseService(healthcheck.PhaseWinner),
+			}
+
+			for _, service := range services {
+				resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+				assert.NoError(t, err)
+
+				want := healthpb.HealthCheckResponse_NOT_SERVING
+				for _, serving := range tc.wantServing {
+					if serving == service {
+						want = healthpb.HealthCheckResponse_SERVING
+					}
+				}
+
+				assert.Equal(t, want, resp.GetStatus(), service)
+			}
+		})
+	}
+}
+
+func TestNilReporter(t *testing.T) {
+	var reporter *healthcheck.Reporter
+	reporter.SetPhase(healthcheck.PhaseDead)
+	reporter.Shutdown()
+}
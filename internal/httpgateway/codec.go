@@ -0,0 +1,124 @@
+package httpgateway
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"wildwest/internal/interceptors"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const contentTypeJSON = "application/json"
+
+// maxBodySize limits the size of request and response bodies
+const maxBodySize = 1 << 20
+
+var (
+	marshalOptions   = protojson.MarshalOptions{EmitUnpopulated: true}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// httpStatusFromCode maps a gRPC status code to the closest HTTP status code
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// readProto decodes a protojson request body into msg
+func readProto(r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "read body: %v", err)
+	}
+
+	if err := unmarshalOptions.Unmarshal(body, msg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "unmarshal body: %v", err)
+	}
+
+	return nil
+}
+
+// writeProto encodes msg as protojson and writes it with the given HTTP status code
+func writeProto(w http.ResponseWriter, code int, msg proto.Message) {
+	body, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// writeError writes err as a protojson google.rpc.Status with a matching HTTP status code and its retry delay as Retry-After
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+
+	if retryDelay, ok := interceptors.RetryDelay(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryDelay.Seconds()))))
+	}
+
+	writeProto(w, httpStatusFromCode(st.Code()), st.Proto())
+}
+
+// readResponse decodes a response into msg, or converts an error response back into a gRPC status error
+func readResponse(resp *http.Response, msg proto.Message) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		st := &spb.Status{}
+		if err := unmarshalOptions.Unmarshal(body, st); err != nil {
+			// a plain 404 comes from a server which doesn't serve the path at all
+			if resp.StatusCode == http.StatusNotFound {
+				return status.Errorf(codes.Unimplemented, "unexpected http status %d: %s", resp.StatusCode, body)
+			}
+
+			return status.Errorf(codes.Unknown, "unexpected http status %d: %s", resp.StatusCode, body)
+		}
+
+		return status.ErrorProto(st)
+	}
+
+	if err := unmarshalOptions.Unmarshal(body, msg); err != nil {
+		return fmt.Errorf("unmarshal response body: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package httpgateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
+	shootoutpb "wildwest/api/proto/shootout"
+	"wildwest/internal/interceptors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DamagePath       = "/v1/damage"
+	DamageV2Path     = "/v2/damage"
+	ShootoutTimePath = "/v1/shootout-time"
+)
+
+// RegisterDamageService serves the DamageService methods on the given mux using protojson mappings,
+// requests go through the same server interceptors as gRPC requests
+func RegisterDamageService(mux *http.ServeMux, srv damagepb.DamageServiceServer, chain []grpc.UnaryServerInterceptor) {
+	mux.Handle(DamagePath, unaryHandler(
+		chain,
+		damagepb.DamageService_ReceiveDamage_FullMethodName,
+		func() *damagepb.DamageRequest { return &damagepb.DamageRequest{} },
+		srv.ReceiveDamage,
+	))
+}
+
+// RegisterDamageV2Service serves the v2 DamageService unary methods on the given mux using protojson mappings,
+// requests go through the same server interceptors as gRPC requests
+func RegisterDamageV2Service(mux *http.ServeMux, srv damagev2pb.DamageServiceServer, chain []grpc.UnaryServerInterceptor) {
+	mux.Handle(DamageV2Path, unaryHandler(
+		chain,
+		damagev2pb.DamageService_ReceiveDamage_FullMethodName,
+		func() *damagev2pb.DamageRequest { return &damagev2pb.DamageRequest{} },
+		srv.ReceiveDamage,
+	))
+}
+
+// RegisterShootoutService serves the ShootoutService methods on the given mux using protojson mappings,
+// requests go through the same server interceptors as gRPC requests
+func RegisterShootoutService(mux *http.ServeMux, srv shootoutpb.ShootoutServiceServer, chain []grpc.UnaryServerInterceptor) {
+	mux.Handle(ShootoutTimePath, unaryHandler(
+		chain,
+		shootoutpb.ShootoutService_ReceiveShootoutTime_FullMethodName,
+		func() *shootoutpb.ReceiveShootoutTimeRequest { return &shootoutpb.ReceiveShootoutTimeRequest{} },
+		srv.ReceiveShootoutTime,
+	))
+}
+
+// unaryHandler adapts a unary gRPC method to a POST handler
+func unaryHandler[Req proto.Message, Resp proto.Message](
+	chain []grpc.UnaryServerInterceptor,
+	fullMethod string,
+	newReq func() Req,
+	method func(context.Context, Req) (Resp, error),
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, status.Errorf(codes.Unimplemented, "method %s not allowed", r.Method))
+			return
+		}
+
+		req := newReq()
+		if err := readProto(r, req); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		resp, err := interceptors.ServerInvoke(peerContext(r), chain, fullMethod, req, func(ctx context.Context, req any) (any, error) {
+			return method(ctx, req.(Req))
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeProto(w, http.StatusOK, resp.(proto.Message))
+	})
+}
+
+// peerContext attaches the caller's address, TLS state and headers as metadata to the request context the same way
+// gRPC does, so that handlers can treat HTTP callers like gRPC callers
+func peerContext(r *http.Request) context.Context {
+	p := &peer.Peer{Addr: remoteAddr(r.RemoteAddr)}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		md.Append(strings.ToLower(key), values...)
+	}
+
+	return metadata.NewIncomingContext(peer.NewContext(r.Context(), p), md)
+}
+
+type remoteAddr string
+
+func (ra remoteAddr) Network() string { return "tcp" }
+func (ra remoteAddr) String() string  { return string(ra) }
+
+// Call posts req as protojson to url and decodes the response into resp
+func Call(ctx context.Context, client *http.Client, url string, req proto.Message, resp proto.Message) error {
+	body, err := marshalOptions.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	// propagate outgoing metadata as headers
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			httpReq.Header.Add(key, value)
+		}
+	}
+
+	httpReq.Header.Set("Content-Type", contentTypeJSON)
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer httpResp.Body.Close()
+
+	return readResponse(httpResp, resp)
+}
+
+// ReceiveDamage calls DamageService.ReceiveDamage over HTTP on the given base url
+func ReceiveDamage(ctx context.Context, client *http.Client, baseURL string, req *damagepb.DamageRequest) error {
+	return Call(ctx, client, baseURL+DamagePath, req, &emptypb.Empty{})
+}
+
+// ReceiveDamageV2 calls the v2 DamageService.ReceiveDamage over HTTP on the given base url
+func ReceiveDamageV2(ctx context.Context, client *http.Client, baseURL string, req *damagev2pb.DamageRequest) (*damagev2pb.DamageResponse, error) {
+	resp := &damagev2pb.DamageResponse{}
+	if err := Call(ctx, client, baseURL+DamageV2Path, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ReceiveShootoutTime calls ShootoutService.ReceiveShootoutTime over HTTP on the given base url
+func ReceiveShootoutTime(ctx context.Context, client *http.Client, baseURL string, req *shootoutpb.ReceiveShootoutTimeRequest) error {
+	return Call(ctx, client, baseURL+ShootoutTimePath, req, &emptypb.Empty{})
+}
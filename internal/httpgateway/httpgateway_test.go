@@ -0,0 +1,129 @@
+package httpgateway_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
+	shootoutpb "wildwest/api/proto/shootout"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/handlers/damagehandler"
+	"wildwest/internal/handlers/shootouthandler"
+	"wildwest/internal/httpgateway"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestReceiveDamage(t *testing.T) {
+	tests := []struct {
+		name          string
+		shooterHealth int
+		damage        int64
+		wantHealth    string
+		wantCode      codes.Code
+	}{
+		{"alive shooter", 5, 3, "7", codes.OK},
+		{"dead shooter", 0, 3, "10", codes.Aborted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "10"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", strconv.Itoa(tc.shooterHealth)))
+
+			mux := http.NewServeMux()
+			httpgateway.RegisterDamageService(mux, damagehandler.NewGRPC(zap.NewNop(), damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {}), nil, nil), nil)
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			// execute
+			err := httpgateway.ReceiveDamage(ctx, server.Client(), server.URL, &damagepb.DamageRequest{From: 2, Damage: tc.damage})
+
+			// verify
+			assert.Equal(t, tc.wantCode, status.Code(err))
+
+			got, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHealth, got)
+		})
+	}
+}
+
+func TestReceiveDamageV2(t *testing.T) {
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "10"))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", "5"))
+
+	damageApplier := damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {})
+
+	mux := http.NewServeMux()
+	httpgateway.RegisterDamageV2Service(mux, damagehandler.NewGRPCV2(zap.NewNop(), damageApplier, nil, nil), nil)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := httpgateway.ReceiveDamageV2(ctx, server.Client(), server.URL, &damagev2pb.DamageRequest{From: 2, Damage: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, damagev2pb.Outcome_OUTCOME_HIT, resp.GetOutcome())
+	assert.Equal(t, int64(7), resp.GetRemainingHealth())
+
+	// servers without the v2 API answer with a plain 404
+	legacyServer := httptest.NewServer(http.NewServeMux())
+	defer legacyServer.Close()
+
+	_, err = httpgateway.ReceiveDamageV2(ctx, legacyServer.Client(), legacyServer.URL, &damagev2pb.DamageRequest{From: 2, Damage: 3})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestReceiveShootoutTime(t *testing.T) {
+	shootoutManager := shootoutstarter.New(zap.NewNop())
+
+	mux := http.NewServeMux()
+	httpgateway.RegisterShootoutService(mux, shootouthandler.NewGRPC(shootoutManager), nil)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	shootoutTime := time.Now().Add(300 * time.Millisecond)
+
+	err := httpgateway.ReceiveShootoutTime(context.Background(), server.Client(), server.URL, &shootoutpb.ReceiveShootoutTimeRequest{StartTime: timestamppb.New(shootoutTime)})
+	assert.NoError(t, err)
+
+	assert.NoError(t, shootoutManager.WaitForShootout(context.Background()))
+	assert.False(t, time.Now().Before(shootoutTime))
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	mux := http.NewServeMux()
+	httpgateway.RegisterShootoutService(mux, shootouthandler.NewGRPC(shootoutstarter.New(zap.NewNop())), nil)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + httpgateway.ShootoutTimePath)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
@@ -0,0 +1,202 @@
+package interceptors
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	CallerIDKey = "x-caller-id"
+	GameIDKey   = "x-game-id"
+
+	maxBackoff = 5 * time.Second
+)
+
+// ClientConfig enables and configures each part of the outbound interceptor chain
+type ClientConfig struct {
+	RetryEnabled bool
+	RetryBudgets map[codes.Code]int
+	RetryBackoff time.Duration
+
+	DeadlinesEnabled bool
+	DefaultDeadline  time.Duration
+	MethodDeadlines  map[string]time.Duration
+
+	MetadataEnabled bool
+	CallerID        string
+	GameID          *GameID
+
+	LoggingEnabled bool
+}
+
+// NewClientChain builds the enabled unary client interceptors, ordered logging, metadata, deadline, retry,
+// so that the deadline bounds all retry attempts and the log line covers the whole call
+func NewClientChain(logger *zap.Logger, cfg ClientConfig) []grpc.UnaryClientInterceptor {
+	var chain []grpc.UnaryClientInterceptor
+
+	if cfg.LoggingEnabled {
+		chain = append(chain, ClientLogging(logger))
+	}
+
+	if cfg.MetadataEnabled {
+		chain = append(chain, ClientMetadata(cfg.CallerID, cfg.GameID))
+	}
+
+	if cfg.DeadlinesEnabled {
+		chain = append(chain, ClientDeadline(cfg.DefaultDeadline, cfg.MethodDeadlines))
+	}
+
+	if cfg.RetryEnabled {
+		chain = append(chain, ClientRetry(cfg.RetryBudgets, cfg.RetryBackoff))
+	}
+
+	return chain
+}
+
+// Invoke runs invoker through the interceptors the way a grpc.ClientConn does, used for transports other than gRPC
+func Invoke(ctx context.Context, chain []grpc.UnaryClientInterceptor, method string, req, reply any, invoker grpc.UnaryInvoker) error {
+	if len(chain) == 0 {
+		return invoker(ctx, method, req, reply, nil)
+	}
+
+	return chain[0](ctx, method, req, reply, nil, func(ctx context.Context, method string, req, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		return Invoke(ctx, chain[1:], method, req, reply, invoker)
+	})
+}
+
+// ClientRetry retries failed calls while the retry budget of the returned status code isn't spent,
+// waiting an exponential backoff with full jitter between attempts, or longer if the server asked to retry later
+func ClientRetry(budgets map[codes.Code]int, backoff time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		retries := make(map[codes.Code]int)
+
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil {
+				return nil
+			}
+
+			code := status.Code(err)
+			if retries[code] >= budgets[code] {
+				return err
+			}
+
+			retries[code]++
+
+			wait := backoffDuration(backoff, attempt)
+			if retryDelay, ok := RetryDelay(err); ok && retryDelay > wait {
+				wait = retryDelay
+			}
+
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// backoffDuration returns a random duration up to base*2^attempt, capped at maxBackoff
+func backoffDuration(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	upperBound := base << attempt
+	if upperBound <= 0 || upperBound > maxBackoff {
+		upperBound = maxBackoff
+	}
+
+	return time.Duration(rand.Int63n(int64(upperBound)))
+}
+
+// ClientDeadline bounds each call by the method's deadline, or by the default deadline if the method has none
+func ClientDeadline(defaultDeadline time.Duration, methodDeadlines map[string]time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		deadline, ok := methodDeadlines[method]
+		if !ok {
+			deadline = defaultDeadline
+		}
+
+		if deadline > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, deadline)
+			defer cancel()
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// GameID holds the id of the game being played, it changes when the next scheduled game begins. A nil GameID holds
+// no id
+type GameID struct {
+	id atomic.Value
+}
+
+// NewGameID returns a GameID holding the given id
+func NewGameID(id string) *GameID {
+	gameID := &GameID{}
+	gameID.Set(id)
+
+	return gameID
+}
+
+// Set replaces the id of the game being played
+func (g *GameID) Set(id string) {
+	g.id.Store(id)
+}
+
+// Get returns the id of the game being played, empty if unknown
+func (g *GameID) Get() string {
+	if g == nil {
+		return ""
+	}
+
+	id, _ := g.id.Load().(string)
+
+	return id
+}
+
+// ClientMetadata propagates the caller id and the id of the game being played when the call is made in the outgoing
+// metadata
+func ClientMetadata(callerID string, gameID *GameID) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		kv := []string{CallerIDKey, callerID}
+		if id := gameID.Get(); id != "" {
+			kv = append(kv, GameIDKey, id)
+		}
+
+		return invoker(metadata.AppendToOutgoingContext(ctx, kv...), method, req, reply, cc, opts...)
+	}
+}
+
+// ClientLogging logs every outbound call with its status code and latency
+func ClientLogging(logger *zap.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		logger.Debug("outbound call",
+			zap.String("method", method),
+			zap.Stringer("code", status.Code(err)),
+			zap.Duration("latency", time.Since(start)),
+			zap.Error(err),
+		)
+
+		return err
+	}
+}
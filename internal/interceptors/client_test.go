@@ -0,0 +1,187 @@
+package interceptors_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/interceptors"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/damagepb.DamageService/ReceiveDamage"
+
+// failingInvoker returns an invoker failing with the given codes in order, then succeeding, and counts its calls
+func failingInvoker(calls *int, failures ...codes.Code) grpc.UnaryInvoker {
+	return func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(failures) {
+			return status.Error(failures[*calls-1], "failure")
+		}
+
+		return nil
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		budgets   map[codes.Code]int
+		failures  []codes.Code
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{"no failures", map[codes.Code]int{codes.Unavailable: 3}, nil, 1, codes.OK},
+		{"failures within budget", map[codes.Code]int{codes.Unavailable: 3}, []codes.Code{codes.Unavailable, codes.Unavailable}, 3, codes.OK},
+		{"budget spent", map[codes.Code]int{codes.Unavailable: 1}, []codes.Code{codes.Unavailable, codes.Unavailable}, 2, codes.Unavailable},
+		{"code without budget", map[codes.Code]int{codes.Unavailable: 3}, []codes.Code{codes.InvalidArgument}, 1, codes.InvalidArgument},
+		{"budgets per code", map[codes.Code]int{codes.Unavailable: 1, codes.ResourceExhausted: 1}, []codes.Code{codes.Unavailable, codes.ResourceExhausted}, 3, codes.OK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+
+			err := interceptors.Invoke(context.Background(), []grpc.UnaryClientInterceptor{
+				interceptors.ClientRetry(tc.budgets, time.Millisecond),
+			}, testMethod, nil, nil, failingInvoker(&calls, tc.failures...))
+
+			assert.Equal(t, tc.wantCode, status.Code(err))
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func TestClientDeadline(t *testing.T) {
+	tests := []struct {
+		name            string
+		methodDeadlines map[string]time.Duration
+		want            time.Duration
+	}{
+		{"default deadline", nil, time.Second},
+		{"method deadline", map[string]time.Duration{testMethod: time.Minute}, time.Minute},
+		{"other method deadline", map[string]time.Duration{"/other": time.Minute}, time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got time.Duration
+
+			err := interceptors.Invoke(context.Background(), []grpc.UnaryClientInterceptor{
+				interceptors.ClientDeadline(time.Second, tc.methodDeadlines),
+			}, testMethod, nil, nil, func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				deadline, ok := ctx.Deadline()
+				assert.True(t, ok)
+				got = time.Until(deadline)
+
+				return nil
+			})
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.want, got, float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestClientMetadata(t *testing.T) {
+	gameID := interceptors.NewGameID("game-1")
+	chain := []grpc.UnaryClientInterceptor{interceptors.ClientMetadata("cowboy-1", gameID)}
+
+	// invoke returns the caller id and the game id propagated by a call
+	invoke := func() (callerID []string, gameID []string) {
+		err := interceptors.Invoke(context.Background(), chain, testMethod, nil, nil, func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			assert.True(t, ok)
+
+			callerID, gameID = md.Get(interceptors.CallerIDKey), md.Get(interceptors.GameIDKey)
+
+			return nil
+		})
+		assert.NoError(t, err)
+
+		return callerID, gameID
+	}
+
+	callerID, id := invoke()
+	assert.Equal(t, []string{"cowboy-1"}, callerID)
+	assert.Equal(t, []string{"game-1"}, id)
+
+	// calls made once the next game began carry its id
+	gameID.Set("game-2")
+
+	_, id = invoke()
+	assert.Equal(t, []string{"game-2"}, id)
+
+	// no id is propagated while it's unknown
+	chain = []grpc.UnaryClientInterceptor{interceptors.ClientMetadata("cowboy-1", nil)}
+
+	_, id = invoke()
+	assert.Empty(t, id)
+}
+
+func TestNewClientChain(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  interceptors.ClientConfig
+		want int
+	}{
+		{"nothing enabled", interceptors.ClientConfig{}, 0},
+		{"everything enabled", interceptors.ClientConfig{RetryEnabled: true, DeadlinesEnabled: true, MetadataEnabled: true, LoggingEnabled: true}, 4},
+		{"retries only", interceptors.ClientConfig{RetryEnabled: true}, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Len(t, interceptors.NewClientChain(zap.NewNop(), tc.cfg), tc.want)
+		})
+	}
+}
+
+func TestParseRetryBudgets(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[codes.Code]int
+		err   error
+	}{
+		{"", map[codes.Code]int{}, nil},
+		{"UNAVAILABLE=3", map[codes.Code]int{codes.Unavailable: 3}, nil},
+		{"unavailable=3, RESOURCE_EXHAUSTED=1", map[codes.Code]int{codes.Unavailable: 3, codes.ResourceExhausted: 1}, nil},
+		{"UNAVAILABLE", nil, interceptors.ErrInvalidKeyValueList},
+		{"FOO=1", nil, interceptors.ErrInvalidKeyValueList},
+		{"UNAVAILABLE=-1", nil, interceptors.ErrInvalidKeyValueList},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := interceptors.ParseRetryBudgets(tc.input)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseMethodDeadlines(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[string]time.Duration
+		err   error
+	}{
+		{"", map[string]time.Duration{}, nil},
+		{testMethod + "=2s", map[string]time.Duration{testMethod: 2 * time.Second}, nil},
+		{testMethod + "=foo", nil, interceptors.ErrInvalidKeyValueList},
+		{testMethod + "=0s", nil, interceptors.ErrInvalidKeyValueList},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := interceptors.ParseMethodDeadlines(tc.input)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package interceptors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wildwest/internal/limiter"
+	"wildwest/internal/metrics"
+	"wildwest/internal/utils"
+
+	"google.golang.org/grpc/codes"
+)
+
+const ErrInvalidKeyValueList = utils.ConstError("invalid key=value list")
+
+// ParseRetryBudgets parses a list such as "UNAVAILABLE=3,RESOURCE_EXHAUSTED=2" into retry budgets by status code
+func ParseRetryBudgets(s string) (map[codes.Code]int, error) {
+	pairs, err := parseKeyValueList(s)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := make(map[codes.Code]int, len(pairs))
+
+	for name, value := range pairs {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(name)))); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKeyValueList, err)
+		}
+
+		budget, err := strconv.Atoi(value)
+		if err != nil || budget < 0 {
+			return nil, fmt.Errorf("%w: invalid retry budget %q for %s", ErrInvalidKeyValueList, value, name)
+		}
+
+		budgets[code] = budget
+	}
+
+	return budgets, nil
+}
+
+// ParseMethodDeadlines parses a list such as "/damagepb.DamageService/ReceiveDamage=2s" into deadlines by full method name
+func ParseMethodDeadlines(s string) (map[string]time.Duration, error) {
+	pairs, err := parseKeyValueList(s)
+	if err != nil {
+		return nil, err
+	}
+
+	deadlines := make(map[string]time.Duration, len(pairs))
+
+	for method, value := range pairs {
+		deadline, err := time.ParseDuration(value)
+		if err != nil || deadline <= 0 {
+			return nil, fmt.Errorf("%w: invalid deadline %q for %s", ErrInvalidKeyValueList, value, method)
+		}
+
+		deadlines[method] = deadline
+	}
+
+	return deadlines, nil
+}
+
+// parseKeyValueList parses a comma separated key=value list, an empty string is an empty list
+func parseKeyValueList(s string) (map[string]string, error) {
+	pairs := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKeyValueList, pair)
+		}
+
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return pairs, nil
+}
+
+// NewClientConfig builds the outbound interceptor chain config from the environment, the id of the game being played
+// is set on its GameID once known
+func NewClientConfig(envConfig utils.Environment, callerID string) (ClientConfig, error) {
+	retryBudgets, err := ParseRetryBudgets(envConfig.ClientRetryBudgets)
+	if err != nil {
+		return ClientConfig{}, fmt.Errorf("parse retry budgets: %w", err)
+	}
+
+	methodDeadlines, err := ParseMethodDeadlines(envConfig.ClientMethodDeadlines)
+	if err != nil {
+		return ClientConfig{}, fmt.Errorf("parse method deadlines: %w", err)
+	}
+
+	return ClientConfig{
+		RetryEnabled:     envConfig.ClientRetryEnabled,
+		RetryBudgets:     retryBudgets,
+		RetryBackoff:     envConfig.ClientRetryBackoff,
+		DeadlinesEnabled: envConfig.ClientDeadlinesEnabled,
+		DefaultDeadline:  envConfig.ClientDefaultDeadline,
+		MethodDeadlines:  methodDeadlines,
+		MetadataEnabled:  envConfig.ClientMetadataEnabled,
+		CallerID:         callerID,
+		GameID:           &GameID{},
+		LoggingEnabled:   envConfig.ClientLoggingEnabled,
+	}, nil
+}
+
+// NewServerConfig builds the inbound interceptor chain config from the environment, the calls of sheddableMethods
+// share one concurrency limiter
+func NewServerConfig(envConfig utils.Environment, validators map[string]Validator, sheddableMethods []string, registry *metrics.Registry) ServerConfig {
+	return ServerConfig{
+		LoggingEnabled:    envConfig.ServerLoggingEnabled,
+		RecoveryEnabled:   envConfig.ServerRecoveryEnabled,
+		ValidationEnabled: envConfig.ServerValidationEnabled,
+		Validators:        validators,
+		MetricsEnabled:    envConfig.ServerMetricsEnabled,
+		Metrics:           registry,
+
+		LoadSheddingEnabled: envConfig.LoadSheddingEnabled,
+		Limiter: limiter.New("damage", limiter.Config{
+			InitialLimit:  envConfig.LoadSheddingInitialLimit,
+			MinLimit:      envConfig.LoadSheddingMinLimit,
+			MaxLimit:      envConfig.LoadSheddingMaxLimit,
+			MaxQueue:      envConfig.LoadSheddingMaxQueue,
+			TargetLatency: envConfig.LoadSheddingTargetLatency,
+		}, registry),
+		LoadSheddingMethods: sheddableMethods,
+	}
+}
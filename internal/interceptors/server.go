@@ -0,0 +1,205 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+	"wildwest/internal/limiter"
+	"wildwest/internal/metrics"
+	"wildwest/internal/tlsconfig"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const ServerHandledMetric = "grpc_server_handled_total"
+
+// Validator checks a request before it reaches the handler
+type Validator func(req any) error
+
+// ServerConfig enables and configures each part of the inbound interceptor chain
+type ServerConfig struct {
+	LoggingEnabled bool
+
+	RecoveryEnabled bool
+
+	ValidationEnabled bool
+	Validators        map[string]Validator
+
+	MetricsEnabled bool
+	Metrics        *metrics.Registry
+
+	LoadSheddingEnabled bool
+	Limiter             *limiter.Limiter
+	LoadSheddingMethods []string
+}
+
+// NewServerChain builds the enabled unary server interceptors, ordered logging, metrics, recovery, validation,
+// load shedding, so that recovered panics and rejected requests are logged and counted with their status code
+// and invalid requests never take a slot of the limiter
+func NewServerChain(logger *zap.Logger, cfg ServerConfig) []grpc.UnaryServerInterceptor {
+	var chain []grpc.UnaryServerInterceptor
+
+	if cfg.LoggingEnabled {
+		chain = append(chain, ServerLogging(logger))
+	}
+
+	if cfg.MetricsEnabled {
+		chain = append(chain, ServerMetrics(cfg.Metrics))
+	}
+
+	if cfg.RecoveryEnabled {
+		chain = append(chain, ServerRecovery(logger))
+	}
+
+	if cfg.ValidationEnabled {
+		chain = append(chain, ServerValidation(cfg.Validators))
+	}
+
+	if cfg.LoadSheddingEnabled {
+		chain = append(chain, ServerLoadShedding(cfg.Limiter, cfg.LoadSheddingMethods))
+	}
+
+	return chain
+}
+
+// ServerInvoke runs handler through the interceptors the way a grpc.Server does, used for transports other than gRPC
+func ServerInvoke(ctx context.Context, chain []grpc.UnaryServerInterceptor, method string, req any, handler grpc.UnaryHandler) (any, error) {
+	if len(chain) == 0 {
+		return handler(ctx, req)
+	}
+
+	return chain[0](ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		return ServerInvoke(ctx, chain[1:], method, req, handler)
+	})
+}
+
+// ServerLogging writes an access log line for every inbound call
+func ServerLogging(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		logger.Info("inbound call",
+			zap.String("caller", callerFromContext(ctx)),
+			zap.String("method", info.FullMethod),
+			zap.Stringer("code", status.Code(err)),
+			zap.Duration("latency", time.Since(start)),
+			zap.Error(err),
+		)
+
+		return resp, err
+	}
+}
+
+// ServerRecovery converts a panic in a handler into a codes.Internal error instead of crashing the process
+func ServerRecovery(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("panic in handler",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+
+				resp, err = nil, status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// ServerValidation rejects requests with codes.InvalidArgument if the method's validator fails
+func ServerValidation(validators map[string]Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if validate, ok := validators[info.FullMethod]; ok {
+			if err := validate(req); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ServerLoadShedding runs calls of the given methods through the concurrency limiter, rejecting them with
+// codes.ResourceExhausted and a RetryInfo detail when its queue is full
+func ServerLoadShedding(l *limiter.Limiter, methods []string) grpc.UnaryServerInterceptor {
+	limited := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		limited[method] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !limited[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		release, retryAfter, err := l.Acquire(ctx)
+		if errors.Is(err, limiter.ErrQueueFull) {
+			st := status.New(codes.ResourceExhausted, err.Error())
+			if withDetails, detailsErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); detailsErr == nil {
+				st = withDetails
+			}
+
+			return nil, st.Err()
+		}
+		if err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// RetryDelay returns the retry delay the server attached to err, if any
+func RetryDelay(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+			return retryInfo.GetRetryDelay().AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
+
+// ServerMetrics counts handled calls by method and status code
+func ServerMetrics(registry *metrics.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+
+		registry.Inc(ServerHandledMetric, "method", info.FullMethod, "code", status.Code(err).String())
+
+		return resp, err
+	}
+}
+
+// callerFromContext identifies the caller by its verified SPIFFE ID, its propagated caller id or its address
+func callerFromContext(ctx context.Context) string {
+	if peerID, ok := tlsconfig.PeerID(ctx); ok {
+		return peerID
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if callerID := md.Get(CallerIDKey); len(callerID) > 0 {
+			return callerID[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+
+	return "unknown"
+}
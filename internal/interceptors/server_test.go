@@ -0,0 +1,121 @@
+package interceptors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/limiter"
+	"wildwest/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerChain(t *testing.T) {
+	errInvalid := errors.New("invalid request")
+
+	tests := []struct {
+		name     string
+		req      any
+		handler  grpc.UnaryHandler
+		wantCode codes.Code
+	}{
+		{
+			name:     "successful call",
+			req:      "valid",
+			handler:  func(context.Context, any) (any, error) { return "ok", nil },
+			wantCode: codes.OK,
+		},
+		{
+			name:     "panicking handler",
+			req:      "valid",
+			handler:  func(context.Context, any) (any, error) { panic("boom") },
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "invalid request",
+			req:      "invalid",
+			handler:  func(context.Context, any) (any, error) { return "ok", nil },
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "handler error",
+			req:      "valid",
+			handler:  func(context.Context, any) (any, error) { return nil, status.Error(codes.NotFound, "not found") },
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := metrics.New()
+
+			chain := interceptors.NewServerChain(zap.NewNop(), interceptors.ServerConfig{
+				LoggingEnabled:    true,
+				RecoveryEnabled:   true,
+				ValidationEnabled: true,
+				Validators: map[string]interceptors.Validator{
+					testMethod: func(req any) error {
+						if req != "valid" {
+							return errInvalid
+						}
+
+						return nil
+					},
+				},
+				MetricsEnabled: true,
+				Metrics:        registry,
+			})
+
+			_, err := interceptors.ServerInvoke(context.Background(), chain, testMethod, tc.req, tc.handler)
+
+			assert.Equal(t, tc.wantCode, status.Code(err))
+			assert.Equal(t, uint64(1), registry.Counter(interceptors.ServerHandledMetric, "method", testMethod, "code", tc.wantCode.String()))
+		})
+	}
+}
+
+func TestServerValidationOtherMethod(t *testing.T) {
+	chain := []grpc.UnaryServerInterceptor{
+		interceptors.ServerValidation(map[string]interceptors.Validator{
+			"/other": func(any) error { return errors.New("invalid request") },
+		}),
+	}
+
+	resp, err := interceptors.ServerInvoke(context.Background(), chain, testMethod, nil, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
+
+func TestServerLoadShedding(t *testing.T) {
+	l := limiter.New("test", limiter.Config{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 0, TargetLatency: time.Second}, nil)
+
+	chain := []grpc.UnaryServerInterceptor{interceptors.ServerLoadShedding(l, []string{testMethod})}
+
+	release, _, err := l.Acquire(context.Background())
+	assert.NoError(t, err)
+	defer release()
+
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	// the limiter is full, so the limited method is shed with a retry delay
+	_, err = interceptors.ServerInvoke(context.Background(), chain, testMethod, nil, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	retryDelay, ok := interceptors.RetryDelay(err)
+	assert.True(t, ok)
+	assert.Greater(t, retryDelay, time.Duration(0))
+
+	// other methods aren't limited
+	resp, err := interceptors.ServerInvoke(context.Background(), chain, "/other", nil, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
@@ -0,0 +1,225 @@
+package intervention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/events"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ErrGameOver      = utils.ConstError("game is over")
+	ErrGameNotActive = utils.ConstError("game isn't running or paused")
+	ErrNotJoined     = utils.ConstError("cowboy didn't join the game")
+	ErrCowboyDead    = utils.ConstError("cowboy is dead")
+	ErrCowboyAlive   = utils.ConstError("cowboy is alive")
+	ErrInvalidValue  = utils.ConstError("invalid value")
+	ErrChanged       = utils.ConstError("health or game phase changed during the intervention")
+)
+
+// RevisionKey changes with every intervention, so that the cowboys refresh their view of the others' health
+const RevisionKey = "intervention-revision"
+
+// Audit describes who intervened and why
+type Audit struct {
+	Actor  string
+	Reason string
+}
+
+// Result is the health of a cowboy before and after an intervention
+type Result struct {
+	PreviousHealth int
+	Health         int
+}
+
+// GameMaster intervenes in the game. Interventions are applied in a transaction on the same keys as the damage
+// appliers, they only succeed if neither the cowboy's health nor the game phase changed since they were read, and are
+// recorded as events
+type GameMaster struct {
+	db       datastore.Datastore
+	roster   []utils.Cowboy
+	recorder *events.Recorder
+}
+
+func New(db datastore.Datastore, roster []utils.Cowboy, recorder *events.Recorder) *GameMaster {
+	return &GameMaster{
+		db:       db,
+		roster:   roster,
+		recorder: recorder,
+	}
+}
+
+// SetHealth sets the health of an alive cowboy, setting it to 0 kills the cowboy
+func (gm *GameMaster) SetHealth(ctx context.Context, id, health int, audit Audit) (Result, error) {
+	if health < 0 {
+		return Result{}, fmt.Errorf("%w: health %d is negative", ErrInvalidValue, health)
+	}
+
+	return gm.intervene(ctx, id, events.TypeHealthSet, "health set", audit, func(_ gamephase.Phase, current int) (int, error) {
+		if current <= 0 {
+			return 0, fmt.Errorf("%w: revive cowboy %d instead", ErrCowboyDead, id)
+		}
+
+		return health, nil
+	})
+}
+
+// Revive brings a dead cowboy back into a running or paused game with the given health, its health from the roster
+// if it's 0
+func (gm *GameMaster) Revive(ctx context.Context, id, health int, audit Audit) (Result, error) {
+	if health < 0 {
+		return Result{}, fmt.Errorf("%w: health %d is negative", ErrInvalidValue, health)
+	}
+
+	if health == 0 && id >= 0 && id < len(gm.roster) {
+		health = int(gm.roster[id].Health)
+	}
+
+	return gm.intervene(ctx, id, events.TypeRevived, "cowboy revived", audit, func(phase gamephase.Phase, current int) (int, error) {
+		if phase != gamephase.Running && phase != gamephase.Paused {
+			return 0, fmt.Errorf("%w: game is %s", ErrGameNotActive, phase)
+		}
+
+		if current > 0 {
+			return 0, fmt.Errorf("%w: cowboy %d has %d health", ErrCowboyAlive, id, current)
+		}
+
+		return health, nil
+	})
+}
+
+// Kick removes an alive cowboy from the game as if it forfeited
+func (gm *GameMaster) Kick(ctx context.Context, id int, audit Audit) (Result, error) {
+	return gm.intervene(ctx, id, events.TypeKicked, "cowboy kicked", audit, func(_ gamephase.Phase, current int) (int, error) {
+		if current <= 0 {
+			return 0, fmt.Errorf("%w: cowboy %d", ErrCowboyDead, id)
+		}
+
+		return 0, nil
+	})
+}
+
+// Smite deals damage to an alive cowboy like a shot would, without a shooter
+func (gm *GameMaster) Smite(ctx context.Context, id, damage int, audit Audit) (Result, error) {
+	if damage <= 0 {
+		return Result{}, fmt.Errorf("%w: damage %d isn't positive", ErrInvalidValue, damage)
+	}
+
+	return gm.intervene(ctx, id, events.TypeSmitten, "cowboy smitten", audit, func(_ gamephase.Phase, current int) (int, error) {
+		if current <= 0 {
+			return 0, fmt.Errorf("%w: cowboy %d", ErrCowboyDead, id)
+		}
+
+		health := current - damage
+		if health < 0 {
+			health = 0
+		}
+
+		return health, nil
+	}, zap.Int("damage", damage))
+}
+
+// intervene reads the game phase and the cowboy's health, lets change compute the new health and stores it. Dead
+// cowboys are persisted as dead and revived ones as shooting, so that they neither resume shooting nor stay out of the
+// game after restarting
+func (gm *GameMaster) intervene(ctx context.Context, id int, eventType string, message string, audit Audit, change func(phase gamephase.Phase, current int) (int, error), fields ...zap.Field) (Result, error) {
+	if id < 0 || id >= len(gm.roster) {
+		return Result{}, fmt.Errorf("%w: cowboy id %d isn't in the roster", ErrInvalidValue, id)
+	}
+
+	phase, phaseCmp, err := getPhase(ctx, gm.db)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if phase.Terminal() {
+		return Result{}, fmt.Errorf("%w: game is %s", ErrGameOver, phase)
+	}
+
+	healthKey := utils.CowboyKeyPrefix + strconv.Itoa(id)
+
+	currentStr, err := gm.db.Get(ctx, healthKey)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return Result{}, fmt.Errorf("%w: cowboy %d", ErrNotJoined, id)
+	}
+
+	if err != nil {
+		return Result{}, fmt.Errorf("get health: %w", err)
+	}
+
+	current, err := strconv.Atoi(currentStr)
+	if err != nil {
+		return Result{}, fmt.Errorf("parse health: %w", err)
+	}
+
+	health, err := change(phase, current)
+	if err != nil {
+		return Result{}, err
+	}
+
+	ops := []datastore.Op{
+		datastore.OpPut(healthKey, strconv.Itoa(health)),
+		datastore.OpPut(RevisionKey, strconv.FormatInt(time.Now().UnixNano(), 10)),
+	}
+
+	switch {
+	case health <= 0:
+		ops = append(ops,
+			datastore.OpPut(shootoutstarter.PhaseKey(id), string(healthcheck.PhaseDead)),
+			damageapplier.OpRecordDeath(id, damageapplier.NoKiller))
+	case current <= 0:
+		ops = append(ops,
+			datastore.OpPut(shootoutstarter.PhaseKey(id), string(healthcheck.PhaseShooting)),
+			datastore.OpDelete(damageapplier.DeathKey(id)))
+	}
+
+	err = gm.db.Transaction(ctx).If(
+		phaseCmp,
+		datastore.Compare(healthKey, "=", currentStr),
+	).Then(ops...).Commit()
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return Result{}, fmt.Errorf("%w: cowboy %d", ErrChanged, id)
+	}
+
+	if err != nil {
+		return Result{}, fmt.Errorf("apply intervention: %w", err)
+	}
+
+	gm.recorder.Record(events.Event{
+		Type:    eventType,
+		Cowboy:  id,
+		Message: message,
+		Fields: append([]zap.Field{
+			zap.String("actor", audit.Actor),
+			zap.String("reason", audit.Reason),
+			zap.Int("previous_health", current),
+			zap.Int("health", health),
+		}, fields...),
+	})
+
+	return Result{PreviousHealth: current, Health: health}, nil
+}
+
+// getPhase returns the game phase and a comparison holding while it doesn't change
+func getPhase(ctx context.Context, db datastore.Datastore) (gamephase.Phase, datastore.Cmp, error) {
+	phase, err := db.Get(ctx, gamephase.Key)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return gamephase.Registering, datastore.CompareAbsent(gamephase.Key), nil
+	}
+
+	if err != nil {
+		return "", datastore.Cmp{}, fmt.Errorf("get game phase: %w", err)
+	}
+
+	return gamephase.Phase(phase), datastore.Compare(gamephase.Key, "=", phase), nil
+}
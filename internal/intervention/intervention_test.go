@@ -0,0 +1,164 @@
+package intervention_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/events"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/intervention"
+	"wildwest/internal/metrics"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var roster = []utils.Cowboy{
+	{Name: "John", Health: 10, Damage: 1},
+	{Name: "Bill", Health: 8, Damage: 2},
+}
+
+func TestIntervene(t *testing.T) {
+	type intervene func(gm *intervention.GameMaster) (intervention.Result, error)
+
+	tests := []struct {
+		name       string
+		gamePhase  gamephase.Phase
+		health     string
+		intervene  intervene
+		wantErr    error
+		wantHealth string
+		wantPhase  healthcheck.Phase
+		wantEvent  string
+	}{
+		{"heal", gamephase.Running, "3", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.SetHealth(context.Background(), 1, 20, intervention.Audit{})
+		}, nil, "20", healthcheck.PhaseShooting, events.TypeHealthSet},
+		{"set health to 0", gamephase.Paused, "3", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.SetHealth(context.Background(), 1, 0, intervention.Audit{})
+		}, nil, "0", healthcheck.PhaseDead, events.TypeHealthSet},
+		{"set health of dead cowboy", gamephase.Running, "0", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.SetHealth(context.Background(), 1, 5, intervention.Audit{})
+		}, intervention.ErrCowboyDead, "0", healthcheck.PhaseDead, ""},
+		{"set negative health", gamephase.Running, "3", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.SetHealth(context.Background(), 1, -1, intervention.Audit{})
+		}, intervention.ErrInvalidValue, "3", healthcheck.PhaseShooting, ""},
+		{"revive with roster health", gamephase.Running, "0", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Revive(context.Background(), 1, 0, intervention.Audit{})
+		}, nil, "8", healthcheck.PhaseShooting, events.TypeRevived},
+		{"revive with health", gamephase.Paused, "0", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Revive(context.Background(), 1, 2, intervention.Audit{})
+		}, nil, "2", healthcheck.PhaseShooting, events.TypeRevived},
+		{"revive alive cowboy", gamephase.Running, "3", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Revive(context.Background(), 1, 0, intervention.Audit{})
+		}, intervention.ErrCowboyAlive, "3", healthcheck.PhaseShooting, ""},
+		{"revive before the game runs", gamephase.Countdown, "0", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Revive(context.Background(), 1, 0, intervention.Audit{})
+		}, intervention.ErrGameNotActive, "0", healthcheck.PhaseDead, ""},
+		{"kick", gamephase.Registering, "8", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Kick(context.Background(), 1, intervention.Audit{})
+		}, nil, "0", healthcheck.PhaseDead, events.TypeKicked},
+		{"kick after the game finished", gamephase.Finished, "8", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Kick(context.Background(), 1, intervention.Audit{})
+		}, intervention.ErrGameOver, "8", healthcheck.PhaseShooting, ""},
+		{"smite", gamephase.Running, "8", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Smite(context.Background(), 1, 3, intervention.Audit{})
+		}, nil, "5", healthcheck.PhaseShooting, events.TypeSmitten},
+		{"smite to death", gamephase.Running, "2", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Smite(context.Background(), 1, 3, intervention.Audit{})
+		}, nil, "0", healthcheck.PhaseDead, events.TypeSmitten},
+		{"smite without damage", gamephase.Running, "2", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Smite(context.Background(), 1, 0, intervention.Audit{})
+		}, intervention.ErrInvalidValue, "2", healthcheck.PhaseShooting, ""},
+		{"smite cowboy out of roster", gamephase.Running, "2", func(gm *intervention.GameMaster) (intervention.Result, error) {
+			return gm.Smite(context.Background(), 2, 1, intervention.Audit{})
+		}, intervention.ErrInvalidValue, "2", healthcheck.PhaseShooting, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.gamePhase)))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", tc.health))
+
+			phase := healthcheck.PhaseShooting
+			if tc.health == "0" {
+				phase = healthcheck.PhaseDead
+			}
+
+			assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(1), string(phase)))
+
+			registry := metrics.New()
+			gameMaster := intervention.New(fakeDatastore, roster, events.New(zap.NewNop(), registry))
+
+			// execute
+			result, err := tc.intervene(gameMaster)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			health, getErr := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, getErr)
+			assert.Equal(t, tc.wantHealth, health)
+
+			gotPhase, getErr := fakeDatastore.Get(ctx, shootoutstarter.PhaseKey(1))
+			assert.NoError(t, getErr)
+			assert.Equal(t, string(tc.wantPhase), gotPhase)
+
+			_, getErr = fakeDatastore.Get(ctx, intervention.RevisionKey)
+			assert.Equal(t, tc.wantErr == nil, getErr == nil)
+
+			if tc.wantErr == nil {
+				assert.Equal(t, tc.health, strconv.Itoa(result.PreviousHealth))
+				assert.Equal(t, tc.wantHealth, strconv.Itoa(result.Health))
+				assert.Equal(t, uint64(1), registry.Counter(events.EventsMetric, "type", tc.wantEvent))
+			}
+		})
+	}
+}
+
+func TestWaitForRevival(t *testing.T) {
+	tests := []struct {
+		name        string
+		intervene   func(ctx context.Context, db datastore.Datastore, gm *intervention.GameMaster)
+		wantRevived bool
+	}{
+		{"revived", func(ctx context.Context, _ datastore.Datastore, gm *intervention.GameMaster) {
+			_, err := gm.Revive(ctx, 0, 0, intervention.Audit{})
+			assert.NoError(t, err)
+		}, true},
+		{"game over", func(ctx context.Context, db datastore.Datastore, _ *intervention.GameMaster) {
+			assert.NoError(t, gamephase.Transition(ctx, db, gamephase.Running, gamephase.Finished))
+		}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+			assert.NoError(t, shootoutstarter.Forfeit(ctx, fakeDatastore, 0))
+
+			gameMaster := intervention.New(fakeDatastore, roster, nil)
+
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				tc.intervene(ctx, fakeDatastore, gameMaster)
+			}()
+
+			// execute
+			revived := intervention.WaitForRevival(ctx, zap.NewNop(), fakeDatastore, 0, 5*time.Millisecond)
+
+			// verify
+			assert.Equal(t, tc.wantRevived, revived)
+		})
+	}
+}
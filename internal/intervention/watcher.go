@@ -0,0 +1,99 @@
+package intervention
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/shootoutstarter"
+
+	"go.uber.org/zap"
+)
+
+// Watcher polls the intervention revision and calls onChange once it changes, e.g. to refresh the view of the
+// cowboys' health used for targeting
+type Watcher struct {
+	logger       *zap.Logger
+	db           datastore.Datastore
+	pollInterval time.Duration
+	onChange     func()
+}
+
+func NewWatcher(logger *zap.Logger, db datastore.Datastore, pollInterval time.Duration, onChange func()) *Watcher {
+	return &Watcher{
+		logger:       logger,
+		db:           db,
+		pollInterval: pollInterval,
+		onChange:     onChange,
+	}
+}
+
+// Run polls the intervention revision until ctx is done, call is blocking
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	known := false
+	revision := ""
+
+	for {
+		latest, err := w.db.Get(ctx, RevisionKey)
+		switch {
+		case errors.Is(err, datastore.ErrKeyNotFound):
+			latest, err = "", nil
+		case err != nil && ctx.Err() == nil:
+			w.logger.Warn("poll intervention revision", zap.Error(err))
+		}
+
+		if err == nil {
+			// our view is fresh when we start watching
+			if known && latest != revision {
+				w.logger.Debug("game master intervened", zap.String("revision", latest))
+				w.onChange()
+			}
+
+			known = true
+			revision = latest
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForRevival polls the phase of a dead cowboy until it's revived and returns true, it returns false once the game
+// is over or ctx is done
+func WaitForRevival(ctx context.Context, logger *zap.Logger, db datastore.Datastore, id int, pollInterval time.Duration) bool {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		gamePhase, err := gamephase.Get(ctx, db)
+		switch {
+		case err != nil:
+			logger.Warn("poll game phase", zap.Error(err))
+		case gamePhase.Terminal():
+			return false
+		default:
+			phase, err := db.Get(ctx, shootoutstarter.PhaseKey(id))
+			if err == nil && phase != string(healthcheck.PhaseDead) {
+				return true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package limiter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+	"wildwest/internal/metrics"
+	"wildwest/internal/utils"
+)
+
+const ErrQueueFull = utils.ConstError("too many requests queued")
+
+const (
+	QueueDepthMetric = "limiter_queue_depth"
+	InFlightMetric   = "limiter_in_flight"
+	LimitMetric      = "limiter_limit"
+	ShedMetric       = "limiter_shed_total"
+)
+
+// latencyDecrease multiplies the limit when a request is slower than the target latency
+const latencyDecrease = 0.9
+
+// Config holds the limiter bounds, the limit starts at InitialLimit and adapts between MinLimit and MaxLimit
+type Config struct {
+	InitialLimit  int
+	MinLimit      int
+	MaxLimit      int
+	MaxQueue      int
+	TargetLatency time.Duration
+}
+
+// Limiter is an adaptive concurrency limiter, the limit grows by one per limit requests faster than the target
+// latency and shrinks multiplicatively on slower ones. Requests above the limit wait in a bounded queue
+type Limiter struct {
+	name    string
+	cfg     Config
+	metrics *metrics.Registry
+
+	mu       *sync.Mutex
+	limit    float64
+	inFlight int
+	waiting  []chan struct{}
+	// avgLatency is an exponential moving average of the request latency
+	avgLatency time.Duration
+}
+
+// New creates a limiter, its metrics are labeled with name. The bounds are clamped so that at least one request runs
+// at a time and the initial limit is within them
+func New(name string, cfg Config, registry *metrics.Registry) *Limiter {
+	if cfg.MinLimit < 1 {
+		cfg.MinLimit = 1
+	}
+
+	if cfg.MaxLimit < cfg.MinLimit {
+		cfg.MaxLimit = cfg.MinLimit
+	}
+
+	if cfg.InitialLimit < cfg.MinLimit {
+		cfg.InitialLimit = cfg.MinLimit
+	} else if cfg.InitialLimit > cfg.MaxLimit {
+		cfg.InitialLimit = cfg.MaxLimit
+	}
+
+	l := &Limiter{
+		name:       name,
+		cfg:        cfg,
+		metrics:    registry,
+		mu:         &sync.Mutex{},
+		limit:      float64(cfg.InitialLimit),
+		avgLatency: cfg.TargetLatency,
+	}
+
+	l.mu.Lock()
+	l.reportNoLock()
+	l.mu.Unlock()
+
+	return l
+}
+
+// Acquire waits until the request may run and returns the function to call once it's done.
+// If the queue is full it returns ErrQueueFull and how long to wait before retrying
+func (l *Limiter) Acquire(ctx context.Context) (release func(), retryAfter time.Duration, err error) {
+	l.mu.Lock()
+
+	if l.inFlight < int(l.limit) && len(l.waiting) == 0 {
+		l.inFlight++
+		l.reportNoLock()
+		l.mu.Unlock()
+
+		return l.releaseFunc(time.Now()), 0, nil
+	}
+
+	if len(l.waiting) >= l.cfg.MaxQueue {
+		retryAfter := l.retryAfterNoLock()
+		if l.metrics != nil {
+			l.metrics.Inc(ShedMetric, "limiter", l.name)
+		}
+		l.mu.Unlock()
+
+		return nil, retryAfter, ErrQueueFull
+	}
+
+	ready := make(chan struct{})
+	l.waiting = append(l.waiting, ready)
+	l.reportNoLock()
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return l.releaseFunc(time.Now()), 0, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i, w := range l.waiting {
+			if w == ready {
+				l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+				l.reportNoLock()
+
+				return nil, 0, ctx.Err()
+			}
+		}
+
+		// we were handed a slot in the meantime, pass it on
+		l.inFlight--
+		l.admitNoLock()
+		l.reportNoLock()
+
+		return nil, 0, ctx.Err()
+	}
+}
+
+func (l *Limiter) releaseFunc(start time.Time) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.release(time.Since(start))
+		})
+	}
+}
+
+// release adapts the limit to the latency of the finished request and admits waiting requests
+func (l *Limiter) release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+
+	l.avgLatency = (l.avgLatency*9 + latency) / 10
+
+	if latency <= l.cfg.TargetLatency {
+		l.limit += 1 / l.limit
+	} else {
+		l.limit *= latencyDecrease
+	}
+
+	l.limit = math.Max(float64(l.cfg.MinLimit), math.Min(float64(l.cfg.MaxLimit), l.limit))
+
+	l.admitNoLock()
+	l.reportNoLock()
+}
+
+// admitNoLock hands free slots to waiting requests in arrival order
+func (l *Limiter) admitNoLock() {
+	for len(l.waiting) > 0 && l.inFlight < int(l.limit) {
+		l.inFlight++
+		close(l.waiting[0])
+		l.waiting = l.waiting[1:]
+	}
+}
+
+// retryAfterNoLock estimates how long it takes to drain the queue
+func (l *Limiter) retryAfterNoLock() time.Duration {
+	return time.Duration(float64(len(l.waiting)+1) * float64(l.avgLatency) / l.limit)
+}
+
+func (l *Limiter) reportNoLock() {
+	if l.metrics == nil {
+		return
+	}
+
+	l.metrics.SetGauge(QueueDepthMetric, float64(len(l.waiting)), "limiter", l.name)
+	l.metrics.SetGauge(InFlightMetric, float64(l.inFlight), "limiter", l.name)
+	l.metrics.SetGauge(LimitMetric, math.Floor(l.limit), "limiter", l.name)
+}
@@ -0,0 +1,133 @@
+package limiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/limiter"
+	"wildwest/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterQueue(t *testing.T) {
+	registry := metrics.New()
+
+	l := limiter.New("test", limiter.Config{
+		InitialLimit:  1,
+		MinLimit:      1,
+		MaxLimit:      1,
+		MaxQueue:      1,
+		TargetLatency: time.Second,
+	}, registry)
+
+	release, _, err := l.Acquire(context.Background())
+	assert.NoError(t, err)
+
+	// the second request waits in the queue until the first one is done
+	acquired := make(chan func())
+	go func() {
+		release, _, err := l.Acquire(context.Background())
+		assert.NoError(t, err)
+		acquired <- release
+	}()
+
+	assert.Eventually(t, func() bool {
+		return registry.Gauge(limiter.QueueDepthMetric, "limiter", "test") == 1
+	}, time.Second, time.Millisecond)
+
+	// the third request is shed
+	_, retryAfter, err := l.Acquire(context.Background())
+	assert.ErrorIs(t, err, limiter.ErrQueueFull)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.Equal(t, uint64(1), registry.Counter(limiter.ShedMetric, "limiter", "test"))
+
+	release()
+
+	secondRelease := <-acquired
+	assert.Equal(t, float64(0), registry.Gauge(limiter.QueueDepthMetric, "limiter", "test"))
+	assert.Equal(t, float64(1), registry.Gauge(limiter.InFlightMetric, "limiter", "test"))
+
+	secondRelease()
+	assert.Equal(t, float64(0), registry.Gauge(limiter.InFlightMetric, "limiter", "test"))
+}
+
+func TestLimiterCanceledWhileQueued(t *testing.T) {
+	l := limiter.New("test", limiter.Config{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 1, TargetLatency: time.Second}, nil)
+
+	release, _, err := l.Acquire(context.Background())
+	assert.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err = l.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the canceled request left the queue
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err = l.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLimiterAdapts(t *testing.T) {
+	tests := []struct {
+		name      string
+		latency   time.Duration
+		wantLimit float64
+	}{
+		{"fast requests raise the limit", 0, 5},
+		{"slow requests lower the limit", 20 * time.Millisecond, 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := metrics.New()
+
+			l := limiter.New("test", limiter.Config{
+				InitialLimit:  4,
+				MinLimit:      2,
+				MaxLimit:      5,
+				MaxQueue:      0,
+				TargetLatency: 10 * time.Millisecond,
+			}, registry)
+
+			for i := 0; i < 20; i++ {
+				release, _, err := l.Acquire(context.Background())
+				assert.NoError(t, err)
+
+				time.Sleep(tc.latency)
+				release()
+			}
+
+			assert.Equal(t, tc.wantLimit, registry.Gauge(limiter.LimitMetric, "limiter", "test"))
+		})
+	}
+}
+
+func TestLimiterClampsBounds(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	registry := metrics.New()
+	l := limiter.New("test", limiter.Config{InitialLimit: 0, MinLimit: 0, MaxLimit: 0, MaxQueue: 1, TargetLatency: time.Nanosecond}, registry)
+
+	// execute, the slow request would shrink a limit without bounds below one
+	release, _, err := l.Acquire(ctx)
+	assert.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+	release()
+
+	release, _, err = l.Acquire(ctx)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), registry.Gauge(limiter.LimitMetric, "limiter", "test"))
+
+	release()
+}
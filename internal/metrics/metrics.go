@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry holds counters and gauges identified by a name and label pairs, and serves them in the Prometheus text format
+type Registry struct {
+	mu       *sync.Mutex
+	counters map[string]uint64
+	gauges   map[string]float64
+}
+
+func New() *Registry {
+	return &Registry{
+		mu:       &sync.Mutex{},
+		counters: make(map[string]uint64),
+		gauges:   make(map[string]float64),
+	}
+}
+
+// Inc increments a counter, labels are given as key, value pairs
+func (r *Registry) Inc(name string, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counters[seriesKey(name, labels)]++
+}
+
+// Counter returns the current value of a counter
+func (r *Registry) Counter(name string, labels ...string) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.counters[seriesKey(name, labels)]
+}
+
+// SetGauge sets a gauge to the given value, labels are given as key, value pairs
+func (r *Registry) SetGauge(name string, value float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gauges[seriesKey(name, labels)] = value
+}
+
+// Gauge returns the current value of a gauge
+func (r *Registry) Gauge(name string, labels ...string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.gauges[seriesKey(name, labels)]
+}
+
+// ServeHTTP writes all series in the Prometheus text format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	lines := make([]string, 0, len(r.counters)+len(r.gauges))
+
+	for key, value := range r.counters {
+		lines = append(lines, fmt.Sprintf("%s %d", key, value))
+	}
+
+	for key, value := range r.gauges {
+		lines = append(lines, fmt.Sprintf("%s %g", key, value))
+	}
+	r.mu.Unlock()
+
+	sort.Strings(lines)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// seriesKey builds the series identifier, e.g. name{key="value"}
+func seriesKey(name string, labels []string) string {
+	if len(labels) < 2 {
+		return name
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,32 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wildwest/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := metrics.New()
+
+	registry.Inc("calls_total", "method", "/a", "code", "OK")
+	registry.Inc("calls_total", "method", "/a", "code", "OK")
+	registry.Inc("calls_total", "method", "/b", "code", "OK")
+	registry.SetGauge("queue_depth", 3)
+
+	assert.Equal(t, uint64(2), registry.Counter("calls_total", "method", "/a", "code", "OK"))
+	assert.Equal(t, uint64(0), registry.Counter("calls_total", "method", "/c", "code", "OK"))
+	assert.Equal(t, float64(3), registry.Gauge("queue_depth"))
+
+	rr := httptest.NewRecorder()
+	registry.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `calls_total{method="/a",code="OK"} 2
+calls_total{method="/b",code="OK"} 1
+queue_depth 3
+`, rr.Body.String())
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+type LookupSRVFunc func(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+
+// DNSSRV resolves cowboys from the SRV records of _<endpoint>._tcp.<name>,
+// the record of a cowboy is the one whose target starts with <podName>-<id>
+type DNSSRV struct {
+	lookupSRV LookupSRVFunc
+	service   string
+	name      string
+	podName   string
+}
+
+var _ Resolver = (*DNSSRV)(nil)
+
+func NewDNSSRV(lookupSRV LookupSRVFunc, service string, name string, podName string) *DNSSRV {
+	return &DNSSRV{
+		lookupSRV: lookupSRV,
+		service:   service,
+		name:      name,
+		podName:   podName,
+	}
+}
+
+func (ds *DNSSRV) Resolve(ctx context.Context, id int) (string, error) {
+	_, records, err := ds.lookupSRV(ctx, ds.service, "tcp", ds.name)
+	if err != nil {
+		return "", fmt.Errorf("lookup srv: %w", err)
+	}
+
+	hostLabel := fmt.Sprintf("%s-%d", ds.podName, id)
+
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+
+		if label, _, _ := strings.Cut(target, "."); label == hostLabel {
+			return net.JoinHostPort(target, strconv.Itoa(int(record.Port))), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %d", ErrUnknownPeer, id)
+}
@@ -0,0 +1,53 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wildwest/internal/datastore"
+)
+
+// AddressKeyPrefix prefixes the keys holding the cowboys' registered addresses
+const AddressKeyPrefix = "address-"
+
+// AddressKey returns the datastore key holding the address of a cowboy's endpoint, e.g. address-grpc-1
+func AddressKey(endpoint string, id int) string {
+	return fmt.Sprintf("%s%s-%d", AddressKeyPrefix, endpoint, id)
+}
+
+// Register stores the address of our endpoint so other cowboys can find it
+func Register(ctx context.Context, db datastore.Datastore, endpoint string, id int, address string) error {
+	if err := db.Put(ctx, AddressKey(endpoint, id), address); err != nil {
+		return fmt.Errorf("register %s address: %w", endpoint, err)
+	}
+
+	return nil
+}
+
+// Registry resolves cowboys from the addresses they registered in the datastore
+type Registry struct {
+	db       datastore.Datastore
+	endpoint string
+}
+
+var _ Resolver = (*Registry)(nil)
+
+func NewRegistry(db datastore.Datastore, endpoint string) *Registry {
+	return &Registry{
+		db:       db,
+		endpoint: endpoint,
+	}
+}
+
+func (r *Registry) Resolve(ctx context.Context, id int) (string, error) {
+	address, err := r.db.Get(ctx, AddressKey(r.endpoint, id))
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return "", fmt.Errorf("%w: %d", ErrUnknownPeer, id)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("get %s address: %w", r.endpoint, err)
+	}
+
+	return address, nil
+}
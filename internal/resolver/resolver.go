@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"wildwest/internal/datastore"
+	"wildwest/internal/utils"
+)
+
+const (
+	ErrUnknownPeer          = utils.ConstError("unknown peer")
+	ErrUnknownPeerDiscovery = utils.ConstError("unknown peer discovery")
+)
+
+// Resolver finds the address of a cowboy's endpoint
+type Resolver interface {
+	// Resolve returns the host:port address of the cowboy with the given id
+	Resolve(ctx context.Context, id int) (string, error)
+}
+
+// New creates the resolver selected by PEER_DISCOVERY for the given endpoint (grpc or http) listening on port
+func New(envConfig utils.Environment, db datastore.Datastore, lookupSRV LookupSRVFunc, endpoint string, port int) (Resolver, error) {
+	switch envConfig.PeerDiscovery {
+	case utils.PeerDiscoveryStatefulSet:
+		return NewStatefulSet(envConfig.CowboyAppName, envConfig.CowboyAppName, port), nil
+	case utils.PeerDiscoveryStatic:
+		addresses := envConfig.PeerGRPCAddresses
+		if endpoint == utils.ShotTransportHTTP {
+			addresses = envConfig.PeerHTTPAddresses
+		}
+
+		return NewStatic(splitAddresses(addresses)), nil
+	case utils.PeerDiscoveryDNS:
+		name := envConfig.PeerSRVName
+		if name == "" {
+			name = envConfig.CowboyAppName
+		}
+
+		return NewDNSSRV(lookupSRV, endpoint, name, envConfig.CowboyAppName), nil
+	case utils.PeerDiscoveryRegistry:
+		return NewRegistry(db, endpoint), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPeerDiscovery, envConfig.PeerDiscovery)
+	}
+}
+
+// splitAddresses splits a comma separated address list, the n-th address belongs to cowboy n
+func splitAddresses(addresses string) []string {
+	if addresses == "" {
+		return nil
+	}
+
+	split := strings.Split(addresses, ",")
+	for i := range split {
+		split[i] = strings.TrimSpace(split[i])
+	}
+
+	return split
+}
@@ -0,0 +1,71 @@
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"wildwest/internal/datastore"
+	"wildwest/internal/resolver"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvers(t *testing.T) {
+	ctx := context.Background()
+
+	db := datastore.NewFakeClient()
+	assert.NoError(t, resolver.Register(ctx, db, utils.ShotTransportGRPC, 1, "10.0.0.2:50051"))
+
+	lookupSRV := func(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		if service != "grpc" || proto != "tcp" || name != "cowboy" {
+			return "", nil, errors.New("no such host")
+		}
+
+		return "", []*net.SRV{
+			{Target: "cowboy-10.cowboy.default.svc.cluster.local.", Port: 50051},
+			{Target: "cowboy-1.cowboy.default.svc.cluster.local.", Port: 50052},
+		}, nil
+	}
+
+	tests := []struct {
+		name     string
+		resolver resolver.Resolver
+		id       int
+		want     string
+		err      error
+	}{
+		{"statefulset", resolver.NewStatefulSet("cowboy", "cowboy", 50051), 1, "cowboy-1.cowboy:50051", nil},
+		{"static", resolver.NewStatic([]string{"127.0.0.1:9000", "127.0.0.1:9001"}), 1, "127.0.0.1:9001", nil},
+		{"static unknown peer", resolver.NewStatic([]string{"127.0.0.1:9000"}), 1, "", resolver.ErrUnknownPeer},
+		{"dns srv", resolver.NewDNSSRV(lookupSRV, "grpc", "cowboy", "cowboy"), 1, "cowboy-1.cowboy.default.svc.cluster.local:50052", nil},
+		{"dns srv unknown peer", resolver.NewDNSSRV(lookupSRV, "grpc", "cowboy", "cowboy"), 2, "", resolver.ErrUnknownPeer},
+		{"registry", resolver.NewRegistry(db, utils.ShotTransportGRPC), 1, "10.0.0.2:50051", nil},
+		{"registry unknown peer", resolver.NewRegistry(db, utils.ShotTransportHTTP), 1, "", resolver.ErrUnknownPeer},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.resolver.Resolve(ctx, tc.id)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := resolver.New(utils.Environment{PeerDiscovery: "consul"}, nil, nil, utils.ShotTransportGRPC, 50051)
+	assert.ErrorIs(t, err, resolver.ErrUnknownPeerDiscovery)
+
+	static, err := resolver.New(utils.Environment{
+		PeerDiscovery:     utils.PeerDiscoveryStatic,
+		PeerGRPCAddresses: "127.0.0.1:9000, 127.0.0.1:9001",
+		PeerHTTPAddresses: "127.0.0.1:8000, 127.0.0.1:8001",
+	}, nil, nil, utils.ShotTransportHTTP, 8081)
+	assert.NoError(t, err)
+
+	got, err := static.Resolve(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8001", got)
+}
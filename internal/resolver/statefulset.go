@@ -0,0 +1,28 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+)
+
+// StatefulSet resolves cowboys through the headless service of a Kubernetes StatefulSet
+type StatefulSet struct {
+	podName     string
+	serviceName string
+	port        int
+}
+
+var _ Resolver = (*StatefulSet)(nil)
+
+func NewStatefulSet(podName string, serviceName string, port int) *StatefulSet {
+	return &StatefulSet{
+		podName:     podName,
+		serviceName: serviceName,
+		port:        port,
+	}
+}
+
+// Resolve returns the pod hostname, e.g. cowboy-1.cowboy:50051
+func (ss *StatefulSet) Resolve(_ context.Context, id int) (string, error) {
+	return fmt.Sprintf("%s-%d.%s:%d", ss.podName, id, ss.serviceName, ss.port), nil
+}
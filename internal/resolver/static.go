@@ -0,0 +1,27 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+)
+
+// Static resolves cowboys from a fixed address list, the n-th address belongs to cowboy n
+type Static struct {
+	addresses []string
+}
+
+var _ Resolver = (*Static)(nil)
+
+func NewStatic(addresses []string) *Static {
+	return &Static{
+		addresses: addresses,
+	}
+}
+
+func (s *Static) Resolve(_ context.Context, id int) (string, error) {
+	if id < 0 || id >= len(s.addresses) || s.addresses[id] == "" {
+		return "", fmt.Errorf("%w: %d", ErrUnknownPeer, id)
+	}
+
+	return s.addresses[id], nil
+}
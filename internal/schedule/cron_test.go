@@ -0,0 +1,75 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+	"wildwest/internal/schedule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr error
+	}{
+		{"every minute", "* * * * *", nil},
+		{"lists, ranges and steps", "0,30 18-22/2 1-15 */3 1-5", nil},
+		{"descriptor", "@daily", nil},
+		{"too few fields", "0 20 * *", schedule.ErrInvalidSchedule},
+		{"out of range", "60 * * * *", schedule.ErrInvalidSchedule},
+		{"reversed range", "0 22-18 * * *", schedule.ErrInvalidSchedule},
+		{"zero step", "*/0 * * * *", schedule.ErrInvalidSchedule},
+		{"not a number", "0 noon * * *", schedule.ErrInvalidSchedule},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// execute
+			_, err := schedule.Parse(tc.spec)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2024, time.January, 10, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2024, time.January, 10, 12, 35, 0, 0, time.UTC)},
+		{"every half hour", "*/30 * * * *", time.Date(2024, time.January, 10, 13, 0, 0, 0, time.UTC)},
+		{"friday evening", "0 20 * * 5", time.Date(2024, time.January, 12, 20, 0, 0, 0, time.UTC)},
+		{"day of month or week", "0 0 1 * 1", time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 1 *", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			s, err := schedule.Parse(tc.spec)
+			assert.NoError(t, err)
+
+			// execute
+			next := s.Next(from)
+
+			// verify
+			assert.Equal(t, tc.want, next)
+		})
+	}
+}
+
+func TestNilSchedule(t *testing.T) {
+	var s *schedule.Schedule
+
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
@@ -0,0 +1,162 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/series"
+	"wildwest/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const ErrNoGame = utils.ConstError("no game")
+
+// GameKey holds the game being played and ResultKeyPrefix the results of the games played
+const (
+	GameKey         = "current-game"
+	ResultKeyPrefix = "game-result-"
+)
+
+// ResultKey returns the datastore key holding the result of a game, e.g. game-result-wildwest-1
+func ResultKey(gameID string) string {
+	return ResultKeyPrefix + gameID
+}
+
+// Game is a series of rounds played for a scheduled start
+type Game struct {
+	// Number counts the games played from 1
+	Number int    `json:"number"`
+	ID     string `json:"id"`
+	// ScheduledAt is when the shootout of the first round starts, zero to start once the cowboys are ready
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+}
+
+// NewGame returns the game following the one numbered previous, its id is the base id and the number, e.g. wildwest-2
+func NewGame(baseID string, previous int, scheduledAt time.Time) Game {
+	number := previous + 1
+
+	id := strconv.Itoa(number)
+	if baseID != "" {
+		id = baseID + "-" + id
+	}
+
+	return Game{Number: number, ID: id, ScheduledAt: scheduledAt}
+}
+
+// Result is the outcome of a game
+type Result struct {
+	Game   Game          `json:"game"`
+	Series series.Result `json:"series"`
+}
+
+// Init stores the first game unless a game is already stored, e.g. by a controller before restarting, and returns the
+// stored game
+func Init(ctx context.Context, db datastore.Datastore, game Game) (Game, error) {
+	value, err := json.Marshal(game)
+	if err != nil {
+		return Game{}, fmt.Errorf("marshal game: %w", err)
+	}
+
+	err = db.Transaction(ctx).
+		If(datastore.CompareAbsent(GameKey)).
+		Then(datastore.OpPut(GameKey, string(value))).
+		Commit()
+	if err != nil && !errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return Game{}, fmt.Errorf("init game: %w", err)
+	}
+
+	return Current(ctx, db)
+}
+
+// Current returns the game being played, ErrNoGame before the controller stored the first one
+func Current(ctx context.Context, db datastore.Datastore) (Game, error) {
+	value, err := db.Get(ctx, GameKey)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return Game{}, ErrNoGame
+	}
+
+	if err != nil {
+		return Game{}, fmt.Errorf("get game: %w", err)
+	}
+
+	var game Game
+	if err := json.Unmarshal([]byte(value), &game); err != nil {
+		return Game{}, fmt.Errorf("unmarshal game: %w", err)
+	}
+
+	return game, nil
+}
+
+// StoreResult stores the result of a game once under its id
+func StoreResult(ctx context.Context, db datastore.Datastore, game Game, result series.Result) error {
+	value, err := json.Marshal(Result{Game: game, Series: result})
+	if err != nil {
+		return fmt.Errorf("marshal game result: %w", err)
+	}
+
+	err = db.Transaction(ctx).
+		If(datastore.CompareAbsent(ResultKey(game.ID))).
+		Then(datastore.OpPut(ResultKey(game.ID), string(value))).
+		Commit()
+	if err != nil && !errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return fmt.Errorf("store game result: %w", err)
+	}
+
+	return nil
+}
+
+// GetResult returns the result of a game, datastore.ErrKeyNotFound if it isn't over
+func GetResult(ctx context.Context, db datastore.Datastore, gameID string) (Result, error) {
+	value, err := db.Get(ctx, ResultKey(gameID))
+	if err != nil {
+		return Result{}, fmt.Errorf("get result of game %s: %w", gameID, err)
+	}
+
+	var result Result
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return Result{}, fmt.Errorf("unmarshal result of game %s: %w", gameID, err)
+	}
+
+	return result, nil
+}
+
+// Next resets the game that's over in the from phase for the next game, like series.Reset does, and stores it as the
+// game being played. Only one reset succeeds, the others fail with gamephase.ErrPhaseChanged
+func Next(ctx context.Context, db datastore.Datastore, from gamephase.Phase, roster []utils.Cowboy, game Game) error {
+	value, err := json.Marshal(game)
+	if err != nil {
+		return fmt.Errorf("marshal game: %w", err)
+	}
+
+	return series.Reset(ctx, db, from, roster, datastore.OpPut(GameKey, string(value)))
+}
+
+// WaitForGame polls the game being played until a game numbered after the given one begins and returns it, it returns
+// false once ctx is done
+func WaitForGame(ctx context.Context, logger *zap.Logger, db datastore.Datastore, number int, pollInterval time.Duration) (Game, bool) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		game, err := Current(ctx, db)
+		if err == nil && game.Number > number {
+			return game, true
+		}
+
+		if err != nil && !errors.Is(err, ErrNoGame) && ctx.Err() == nil {
+			logger.Warn("poll game", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return Game{}, false
+		case <-ticker.C:
+		}
+	}
+}
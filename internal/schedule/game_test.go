@@ -0,0 +1,104 @@
+package schedule_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/schedule"
+	"wildwest/internal/series"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var roster = []utils.Cowboy{
+	{Name: "John", Health: 10, Damage: 1},
+	{Name: "Bill", Health: 8, Damage: 2},
+}
+
+func TestNewGame(t *testing.T) {
+	assert.Equal(t, "wildwest-1", schedule.NewGame("wildwest", 0, time.Time{}).ID)
+	assert.Equal(t, "3", schedule.NewGame("", 2, time.Time{}).ID)
+}
+
+func TestInit(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	scheduledAt := time.Date(2024, time.January, 12, 20, 0, 0, 0, time.UTC)
+
+	// execute
+	game, err := schedule.Init(ctx, fakeDatastore, schedule.NewGame("wildwest", 0, scheduledAt))
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, schedule.Game{Number: 1, ID: "wildwest-1", ScheduledAt: scheduledAt}, game)
+
+	// a restarted controller keeps playing the stored game
+	restarted, err := schedule.Init(ctx, fakeDatastore, schedule.NewGame("wildwest", 0, time.Time{}))
+	assert.NoError(t, err)
+	assert.Equal(t, game, restarted)
+}
+
+func TestNextGame(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Finished)))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "4"))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "0"))
+
+	game, err := schedule.Init(ctx, fakeDatastore, schedule.NewGame("wildwest", 0, time.Time{}))
+	assert.NoError(t, err)
+
+	_, err = series.ScoreRound(ctx, fakeDatastore, 1, series.Points{Placement: []int{10, 6}})
+	assert.NoError(t, err)
+
+	result, err := series.StoreResult(ctx, fakeDatastore, series.Result{Rounds: 1, Winner: 0})
+	assert.NoError(t, err)
+	assert.NoError(t, schedule.StoreResult(ctx, fakeDatastore, game, result))
+
+	waited := make(chan schedule.Game, 1)
+
+	go func() {
+		next, ok := schedule.WaitForGame(ctx, zap.NewNop(), fakeDatastore, game.Number, 5*time.Millisecond)
+		assert.True(t, ok)
+		waited <- next
+	}()
+
+	// execute
+	next := schedule.NewGame("wildwest", game.Number, time.Now().Add(time.Hour))
+	err = schedule.Next(ctx, fakeDatastore, gamephase.Finished, roster, next)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, next.ID, (<-waited).ID)
+
+	current, err := schedule.Current(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, "wildwest-2", current.ID)
+
+	gamePhase, err := gamephase.Get(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Registering, gamePhase)
+
+	health, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+	assert.NoError(t, err)
+	assert.Equal(t, "8", health)
+
+	// the series starts over, the result of the last game is kept under its id
+	_, err = series.GetResult(ctx, fakeDatastore)
+	assert.ErrorIs(t, err, series.ErrNoResult)
+
+	results, err := series.RoundResults(ctx, fakeDatastore, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	stored, err := schedule.GetResult(ctx, fakeDatastore, game.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, game, stored.Game)
+	assert.Equal(t, 0, stored.Series.Winner)
+}
@@ -0,0 +1,167 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+type Config struct {
+	DB     datastore.Datastore
+	Roster []utils.Cowboy
+	// Rounds is how many rounds the series has, a single round is a plain game
+	Rounds int
+	Points Points
+	// RoundInterval is the break between two rounds
+	RoundInterval time.Duration
+	// PollInterval is how often the game phase is read while a round is played
+	PollInterval time.Duration
+	// Broadcast starts the round from the Registering phase
+	Broadcast func(ctx context.Context, round int) error
+	// OnRoundStart is called once the round is running, it may be nil
+	OnRoundStart func(round int)
+}
+
+// Run plays the rounds of the series from the current one, scoring each round and the series once it's over. An
+// aborted round aborts the series. A restarted controller resumes the series where it was, call is blocking
+func Run(ctx context.Context, logger *zap.Logger, cfg *Config) (Result, error) {
+	if result, err := GetResult(ctx, cfg.DB); !errors.Is(err, ErrNoResult) {
+		return result, err
+	}
+
+	round, err := CurrentRound(ctx, cfg.DB)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for {
+		logger := logger.With(zap.Int("round", round))
+
+		phase, err := playRound(ctx, logger, cfg, round)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if phase == gamephase.Aborted {
+			logger.Info("round aborted")
+			return finish(ctx, cfg, round, true)
+		}
+
+		result, err := ScoreRound(ctx, cfg.DB, round, cfg.Points)
+		if err != nil {
+			return Result{}, err
+		}
+
+		logger.Info("round over", zap.Int("winner", result.Winner), zap.Ints("placements", result.Placements))
+
+		if round >= cfg.Rounds {
+			return finish(ctx, cfg, round, false)
+		}
+
+		// take a break before the next round
+		select {
+		case <-ctx.Done():
+			return Result{}, ctx.Err()
+		case <-time.After(cfg.RoundInterval):
+		}
+
+		if err := NextRound(ctx, cfg.DB, round+1, phase, cfg.Roster); err != nil {
+			return Result{}, fmt.Errorf("begin round %d: %w", round+1, err)
+		}
+
+		round++
+	}
+}
+
+// playRound starts the round unless it began before we restarted and waits until it's over
+func playRound(ctx context.Context, logger *zap.Logger, cfg *Config, round int) (gamephase.Phase, error) {
+	phase, err := gamephase.Get(ctx, cfg.DB)
+	if err != nil {
+		return "", err
+	}
+
+	switch phase {
+	case gamephase.Registering:
+		err := cfg.Broadcast(ctx, round)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		if err != nil {
+			// the round may have ended on its own, e.g. because it was aborted
+			phase, getErr := gamephase.Get(ctx, cfg.DB)
+			if getErr != nil || !phase.Terminal() {
+				return "", fmt.Errorf("broadcast shootout time: %w", err)
+			}
+
+			logger.Info("round ended during the broadcast", zap.String("game_phase", string(phase)), zap.Error(err))
+		} else if cfg.OnRoundStart != nil {
+			cfg.OnRoundStart(round)
+		}
+	case gamephase.Countdown:
+		// we don't know which cowboys acknowledged the shootout time before we restarted
+		if err := gamephase.Transition(ctx, cfg.DB, gamephase.Countdown, gamephase.Aborted); err != nil {
+			return "", fmt.Errorf("abort game: %w", err)
+		}
+
+		logger.Warn("aborted round interrupted during the countdown")
+	case gamephase.Running, gamephase.Paused:
+		logger.Info("round already began", zap.String("game_phase", string(phase)))
+
+		if cfg.OnRoundStart != nil {
+			cfg.OnRoundStart(round)
+		}
+	}
+
+	return waitForGameOver(ctx, logger, cfg)
+}
+
+// waitForGameOver polls the game phase until the round is finished or aborted
+func waitForGameOver(ctx context.Context, logger *zap.Logger, cfg *Config) (gamephase.Phase, error) {
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		phase, err := gamephase.Get(ctx, cfg.DB)
+		if err == nil && phase.Terminal() {
+			return phase, nil
+		}
+
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("poll game phase", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// finish sums up the rounds scored and stores the result of the series
+func finish(ctx context.Context, cfg *Config, round int, aborted bool) (Result, error) {
+	results, err := RoundResults(ctx, cfg.DB, round)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Rounds:    len(results),
+		Aborted:   aborted,
+		Winner:    -1,
+		Standings: Standings(cfg.Roster, results),
+	}
+
+	if !aborted && len(result.Standings) > 0 {
+		result.Winner = result.Standings[0].ID
+	}
+
+	return StoreResult(ctx, cfg.DB, result)
+}
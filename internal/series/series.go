@@ -0,0 +1,419 @@
+package series
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"wildwest/internal/barrier"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+	"wildwest/internal/winner"
+
+	"go.uber.org/zap"
+)
+
+const ErrNoResult = utils.ConstError("series isn't over")
+
+// RoundKey holds the round being played, RoundResultKeyPrefix the results of the rounds played and ResultKey the result
+// of the series
+const (
+	RoundKey             = "round"
+	RoundResultKeyPrefix = "round-result-"
+	ResultKey            = "series-result"
+)
+
+// RoundResultKey returns the datastore key holding the result of a round, e.g. round-result-1
+func RoundResultKey(round int) string {
+	return RoundResultKeyPrefix + strconv.Itoa(round)
+}
+
+// Points awards points by placement, the first points going to the winner, and by kill
+type Points struct {
+	Placement []int
+	Kill      int
+}
+
+// RoundResult is the outcome of a round
+type RoundResult struct {
+	Round int `json:"round"`
+	// Winner is -1 if the round ended without one
+	Winner int `json:"winner"`
+	// Placements lists the cowboys which joined the round from the winner to the first to die, cowboys which died
+	// without being killed, e.g. because they forfeited, come last
+	Placements []int       `json:"placements"`
+	Kills      map[int]int `json:"kills"`
+	Points     map[int]int `json:"points"`
+}
+
+// Standing is the score of a cowboy over the rounds played
+type Standing struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Wins   int    `json:"wins"`
+	Kills  int    `json:"kills"`
+}
+
+// Result is the outcome of a series
+type Result struct {
+	// Rounds is how many rounds were scored
+	Rounds  int  `json:"rounds"`
+	Aborted bool `json:"aborted"`
+	// Winner is -1 if the series was aborted
+	Winner    int        `json:"winner"`
+	Standings []Standing `json:"standings"`
+}
+
+// CurrentRound returns the round being played, the first one until the series moves on
+func CurrentRound(ctx context.Context, db datastore.Datastore) (int, error) {
+	round, err := db.Get(ctx, RoundKey)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return 1, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("get round: %w", err)
+	}
+
+	return strconv.Atoi(round)
+}
+
+// ScoreRound ranks the cowboys which joined the round that's over, awards their points and stores the result. A round
+// is scored once, scoring it again returns the stored result
+func ScoreRound(ctx context.Context, db datastore.Datastore, round int, points Points) (RoundResult, error) {
+	if result, err := getRoundResult(ctx, db, round); err == nil || !errors.Is(err, datastore.ErrKeyNotFound) {
+		return result, err
+	}
+
+	healths, err := joinedHealths(ctx, db)
+	if err != nil {
+		return RoundResult{}, err
+	}
+
+	deaths, err := damageapplier.Deaths(ctx, db)
+	if err != nil {
+		return RoundResult{}, err
+	}
+
+	result := RoundResult{
+		Round:  round,
+		Winner: -1,
+		Kills:  make(map[int]int),
+		Points: make(map[int]int, len(healths)),
+	}
+
+	record, err := winner.Get(ctx, db)
+	switch {
+	case errors.Is(err, winner.ErrNoWinner):
+	case err != nil:
+		return RoundResult{}, err
+	default:
+		result.Winner = record.ID
+	}
+
+	for id := range healths {
+		result.Placements = append(result.Placements, id)
+	}
+
+	// the winner and whoever is alive first, then the cowboys which died the latest
+	sort.Slice(result.Placements, func(i, j int) bool {
+		a, b := result.Placements[i], result.Placements[j]
+		if rankA, rankB := placementRank(result.Winner, healths, deaths, a), placementRank(result.Winner, healths, deaths, b); rankA != rankB {
+			return rankA < rankB
+		}
+
+		if healths[a] <= 0 && healths[b] <= 0 && !deaths[a].At.Equal(deaths[b].At) {
+			return deaths[a].At.After(deaths[b].At)
+		}
+
+		return a < b
+	})
+
+	for _, death := range deaths {
+		if _, ok := healths[death.Killer]; ok {
+			result.Kills[death.Killer]++
+		}
+	}
+
+	for place, id := range result.Placements {
+		if place < len(points.Placement) {
+			result.Points[id] += points.Placement[place]
+		}
+
+		result.Points[id] += result.Kills[id] * points.Kill
+	}
+
+	value, err := json.Marshal(result)
+	if err != nil {
+		return RoundResult{}, fmt.Errorf("marshal round result: %w", err)
+	}
+
+	err = db.Transaction(ctx).
+		If(datastore.CompareAbsent(RoundResultKey(round))).
+		Then(datastore.OpPut(RoundResultKey(round), string(value))).
+		Commit()
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return getRoundResult(ctx, db, round)
+	}
+
+	if err != nil {
+		return RoundResult{}, fmt.Errorf("store round result: %w", err)
+	}
+
+	return result, nil
+}
+
+// placementRank orders the winner before the cowboys alive, before the ones killed, before the ones which died
+// without being killed
+func placementRank(winnerID int, healths map[int]int, deaths map[int]damageapplier.Death, id int) int {
+	_, killed := deaths[id]
+
+	switch {
+	case id == winnerID:
+		return 0
+	case healths[id] > 0:
+		return 1
+	case killed:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// RoundResults returns the stored results of the rounds up to the given one
+func RoundResults(ctx context.Context, db datastore.Datastore, rounds int) ([]RoundResult, error) {
+	results := make([]RoundResult, 0, rounds)
+
+	for round := 1; round <= rounds; round++ {
+		result, err := getRoundResult(ctx, db, round)
+		if errors.Is(err, datastore.ErrKeyNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func getRoundResult(ctx context.Context, db datastore.Datastore, round int) (RoundResult, error) {
+	value, err := db.Get(ctx, RoundResultKey(round))
+	if err != nil {
+		return RoundResult{}, fmt.Errorf("get result of round %d: %w", round, err)
+	}
+
+	var result RoundResult
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return RoundResult{}, fmt.Errorf("unmarshal result of round %d: %w", round, err)
+	}
+
+	return result, nil
+}
+
+// Standings sums up the round results of the cowboys which played, from the most points to the least. Ties go to the
+// cowboy with more wins, then more kills
+func Standings(roster []utils.Cowboy, results []RoundResult) []Standing {
+	byID := make(map[int]*Standing)
+
+	for _, result := range results {
+		for _, id := range result.Placements {
+			standing, ok := byID[id]
+			if !ok {
+				standing = &Standing{ID: id}
+				if id >= 0 && id < len(roster) {
+					standing.Name = roster[id].Name
+				}
+
+				byID[id] = standing
+			}
+
+			standing.Points += result.Points[id]
+			standing.Kills += result.Kills[id]
+
+			if result.Winner == id {
+				standing.Wins++
+			}
+		}
+	}
+
+	standings := make([]Standing, 0, len(byID))
+	for _, standing := range byID {
+		standings = append(standings, *standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.Wins != b.Wins:
+			return a.Wins > b.Wins
+		case a.Kills != b.Kills:
+			return a.Kills > b.Kills
+		default:
+			return a.ID < b.ID
+		}
+	})
+
+	return standings
+}
+
+// NextRound resets the game that's over in the from phase for the given round: the cowboys which joined the game get
+// their health from the roster back and wait for the start again, and the start time, acknowledgements, readiness,
+// deaths and the winner are cleared
+func NextRound(ctx context.Context, db datastore.Datastore, round int, from gamephase.Phase, roster []utils.Cowboy) error {
+	return reset(ctx, db, from, roster, datastore.OpPut(RoundKey, strconv.Itoa(round)))
+}
+
+// Reset resets the game that's over in the from phase for a new series like NextRound does for a new round, clearing
+// the rounds and the result of the series too. The ops are applied with the reset
+func Reset(ctx context.Context, db datastore.Datastore, from gamephase.Phase, roster []utils.Cowboy, ops ...datastore.Op) error {
+	ops = append(ops,
+		datastore.OpDelete(RoundKey),
+		datastore.OpDeletePrefix(RoundResultKeyPrefix),
+		datastore.OpDelete(ResultKey),
+	)
+
+	return reset(ctx, db, from, roster, ops...)
+}
+
+func reset(ctx context.Context, db datastore.Datastore, from gamephase.Phase, roster []utils.Cowboy, ops ...datastore.Op) error {
+	healths, err := joinedHealths(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	ops = append(ops,
+		datastore.OpDelete(shootoutstarter.StartAtKey),
+		datastore.OpDeletePrefix(shootoutstarter.StartAckKeyPrefix),
+		datastore.OpDeletePrefix(barrier.ReadyKeyPrefix),
+		datastore.OpDeletePrefix(damageapplier.DeathKeyPrefix),
+		datastore.OpDelete(winner.Key),
+	)
+
+	for id := range healths {
+		if id < 0 || id >= len(roster) {
+			continue
+		}
+
+		ops = append(ops,
+			datastore.OpPut(utils.CowboyKeyPrefix+strconv.Itoa(id), strconv.Itoa(int(roster[id].Health))),
+			datastore.OpPut(shootoutstarter.PhaseKey(id), string(healthcheck.PhaseWaiting)),
+		)
+	}
+
+	return gamephase.Restart(ctx, db, from, nil, ops...)
+}
+
+// joinedHealths returns the health of the cowboys which joined the game by their id
+func joinedHealths(ctx context.Context, db datastore.Datastore) (map[int]int, error) {
+	kvs, err := db.GetPrefix(ctx, utils.CowboyKeyPrefix)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return map[int]int{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get cowboy health: %w", err)
+	}
+
+	healths := make(map[int]int, len(kvs))
+
+	for key, value := range kvs {
+		id, err := strconv.Atoi(strings.TrimPrefix(key, utils.CowboyKeyPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("convert cowboy id to int: %w", err)
+		}
+
+		health, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("convert cowboy health to int: %w", err)
+		}
+
+		healths[id] = health
+	}
+
+	return healths, nil
+}
+
+// StoreResult stores the result of the series once, storing it again returns the stored result
+func StoreResult(ctx context.Context, db datastore.Datastore, result Result) (Result, error) {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return Result{}, fmt.Errorf("marshal series result: %w", err)
+	}
+
+	err = db.Transaction(ctx).
+		If(datastore.CompareAbsent(ResultKey)).
+		Then(datastore.OpPut(ResultKey, string(value))).
+		Commit()
+	if errors.Is(err, datastore.ErrTransactionUnsuccessful) {
+		return GetResult(ctx, db)
+	}
+
+	if err != nil {
+		return Result{}, fmt.Errorf("store series result: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetResult returns the result of the series, ErrNoResult if it isn't over
+func GetResult(ctx context.Context, db datastore.Datastore) (Result, error) {
+	value, err := db.Get(ctx, ResultKey)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return Result{}, ErrNoResult
+	}
+
+	if err != nil {
+		return Result{}, fmt.Errorf("get series result: %w", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return Result{}, fmt.Errorf("unmarshal series result: %w", err)
+	}
+
+	return result, nil
+}
+
+// WaitForRound polls the round until the given one begins and returns true, it returns false once the series is over
+// or ctx is done
+func WaitForRound(ctx context.Context, logger *zap.Logger, db datastore.Datastore, round int, pollInterval time.Duration) bool {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := CurrentRound(ctx, db)
+		if err == nil && current >= round {
+			return true
+		}
+
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("poll round", zap.Error(err))
+		}
+
+		if _, err := GetResult(ctx, db); err == nil {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package series_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/series"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+	"wildwest/internal/winner"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var roster = []utils.Cowboy{
+	{Name: "John", Health: 10, Damage: 1},
+	{Name: "Bill", Health: 8, Damage: 2},
+	{Name: "Sam", Health: 6, Damage: 3},
+}
+
+var points = series.Points{Placement: []int{10, 6, 4}, Kill: 2}
+
+func TestScoreRound(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name           string
+		healths        map[int]string
+		deaths         map[int]damageapplier.Death
+		winner         int
+		wantPlacements []int
+		wantPoints     map[int]int
+	}{
+		{"winner killed both", map[int]string{0: "4", 1: "0", 2: "0"}, map[int]damageapplier.Death{
+			1: {Killer: 0, At: now},
+			2: {Killer: 0, At: now.Add(-time.Second)},
+		}, 0, []int{0, 1, 2}, map[int]int{0: 14, 1: 6, 2: 4}},
+		{"last to die places higher", map[int]string{0: "0", 1: "0", 2: "3"}, map[int]damageapplier.Death{
+			0: {Killer: 2, At: now.Add(-time.Second)},
+			1: {Killer: 0, At: now},
+		}, 2, []int{2, 1, 0}, map[int]int{0: 6, 1: 6, 2: 12}},
+		{"forfeited places last", map[int]string{0: "0", 1: "5", 2: "0"}, map[int]damageapplier.Death{
+			2: {Killer: damageapplier.NoKiller, At: now},
+		}, 1, []int{1, 2, 0}, map[int]int{0: 4, 1: 10, 2: 6}},
+		{"not joined", map[int]string{1: "5", 2: "0"}, map[int]damageapplier.Death{
+			2: {Killer: 1, At: now},
+		}, 1, []int{1, 2}, map[int]int{1: 12, 2: 6}},
+		{"aborted without a winner", map[int]string{0: "5", 1: "3"}, nil, -1, []int{0, 1}, map[int]int{0: 10, 1: 6}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+
+			for id, health := range tc.healths {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), health))
+			}
+
+			for id, death := range tc.deaths {
+				value, err := json.Marshal(death)
+				assert.NoError(t, err)
+				assert.NoError(t, fakeDatastore.Put(ctx, damageapplier.DeathKey(id), string(value)))
+			}
+
+			if tc.winner >= 0 {
+				value, err := json.Marshal(winner.Record{ID: tc.winner})
+				assert.NoError(t, err)
+				assert.NoError(t, fakeDatastore.Put(ctx, winner.Key, string(value)))
+			}
+
+			// execute
+			result, err := series.ScoreRound(ctx, fakeDatastore, 1, points)
+
+			// verify
+			assert.NoError(t, err)
+			assert.Equal(t, tc.winner, result.Winner)
+			assert.Equal(t, tc.wantPlacements, result.Placements)
+			assert.Equal(t, tc.wantPoints, result.Points)
+
+			// a round is scored once
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "9"))
+
+			rescored, err := series.ScoreRound(ctx, fakeDatastore, 1, points)
+			assert.NoError(t, err)
+			assert.Equal(t, result.Placements, rescored.Placements)
+		})
+	}
+}
+
+func TestStandings(t *testing.T) {
+	// setup
+	results := []series.RoundResult{
+		{Round: 1, Winner: 0, Placements: []int{0, 1, 2}, Kills: map[int]int{0: 2}, Points: map[int]int{0: 14, 1: 6, 2: 4}},
+		{Round: 2, Winner: 1, Placements: []int{1, 2, 0}, Kills: map[int]int{1: 1, 2: 1}, Points: map[int]int{0: 4, 1: 12, 2: 8}},
+		{Round: 3, Winner: 2, Placements: []int{2, 1}, Kills: map[int]int{2: 1}, Points: map[int]int{1: 6, 2: 12}},
+	}
+
+	// execute
+	standings := series.Standings(roster, results)
+
+	// verify
+	assert.Equal(t, []series.Standing{
+		{ID: 2, Name: "Sam", Points: 24, Wins: 1, Kills: 2},
+		{ID: 1, Name: "Bill", Points: 24, Wins: 1, Kills: 1},
+		{ID: 0, Name: "John", Points: 18, Wins: 1, Kills: 2},
+	}, standings)
+}
+
+func TestNextRound(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Finished)))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "4"))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "0"))
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(1), "dead"))
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.StartAtKey, shootoutstarter.FormatStartAt(time.Now())))
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.StartAckKey(0), "ack"))
+	assert.NoError(t, fakeDatastore.Put(ctx, damageapplier.DeathKey(1), "{}"))
+	assert.NoError(t, fakeDatastore.Put(ctx, winner.Key, "{}"))
+
+	// execute
+	err := series.NextRound(ctx, fakeDatastore, 2, gamephase.Finished, roster)
+
+	// verify
+	assert.NoError(t, err)
+
+	round, err := series.CurrentRound(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, round)
+
+	gamePhase, err := gamephase.Get(ctx, fakeDatastore)
+	assert.NoError(t, err)
+	assert.Equal(t, gamephase.Registering, gamePhase)
+
+	for key, want := range map[string]string{
+		utils.CowboyKeyPrefix + "0": "10",
+		utils.CowboyKeyPrefix + "1": "8",
+		shootoutstarter.PhaseKey(0): "waiting",
+		shootoutstarter.PhaseKey(1): "waiting",
+	} {
+		value, err := fakeDatastore.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, want, value, key)
+	}
+
+	// cowboys which didn't join stay out and what the last round left is cleared
+	for _, key := range []string{utils.CowboyKeyPrefix + "2", shootoutstarter.StartAtKey, shootoutstarter.StartAckKey(0), damageapplier.DeathKey(1), winner.Key} {
+		_, err := fakeDatastore.Get(ctx, key)
+		assert.ErrorIs(t, err, datastore.ErrKeyNotFound, key)
+	}
+
+	// the round is reset once
+	assert.ErrorIs(t, series.NextRound(ctx, fakeDatastore, 2, gamephase.Finished, roster), gamephase.ErrPhaseChanged)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		abortRound  int
+		wantRounds  int
+		wantAborted bool
+		wantWinner  int
+		wantPoints  []int
+	}{
+		{"series won", 0, 3, false, 0, []int{30, 24}},
+		{"aborted in the second round", 2, 1, true, -1, []int{12, 6}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, gamephase.Init(ctx, fakeDatastore))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "10"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "8"))
+
+			var started []int
+
+			cfg := &series.Config{
+				DB:           fakeDatastore,
+				Roster:       roster,
+				Rounds:       3,
+				Points:       points,
+				PollInterval: 5 * time.Millisecond,
+				Broadcast: func(ctx context.Context, round int) error {
+					// the broadcast fails like it does when the game is aborted during it
+					if round == tc.abortRound {
+						_, err := gamephase.Abort(ctx, fakeDatastore)
+						assert.NoError(t, err)
+
+						return gamephase.ErrPhaseChanged
+					}
+
+					assert.NoError(t, gamephase.Transition(ctx, fakeDatastore, gamephase.Registering, gamephase.Countdown))
+					assert.NoError(t, gamephase.Transition(ctx, fakeDatastore, gamephase.Countdown, gamephase.Running))
+
+					// John shoots Bill in odd rounds, Bill shoots John in the others
+					winnerID, loserID := 0, 1
+					if round%2 == 0 {
+						winnerID, loserID = 1, 0
+					}
+
+					assert.NoError(t, fakeDatastore.Transaction(ctx).
+						Then(
+							datastore.OpPut(utils.CowboyKeyPrefix+strconv.Itoa(loserID), "0"),
+							damageapplier.OpRecordDeath(loserID, winnerID),
+						).
+						Commit())
+
+					_, err := winner.Declare(ctx, fakeDatastore, 2, winnerID, roster[winnerID].Name)
+
+					return err
+				},
+				OnRoundStart: func(round int) {
+					started = append(started, round)
+				},
+			}
+
+			// execute
+			result, err := series.Run(ctx, zap.NewNop(), cfg)
+
+			// verify
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRounds, result.Rounds)
+			assert.Equal(t, tc.wantAborted, result.Aborted)
+			assert.Equal(t, tc.wantWinner, result.Winner)
+			assert.Len(t, started, tc.wantRounds)
+
+			gotPoints := make([]int, 0, len(result.Standings))
+			for _, standing := range result.Standings {
+				gotPoints = append(gotPoints, standing.Points)
+			}
+
+			assert.Equal(t, tc.wantPoints, gotPoints)
+
+			stored, err := series.GetResult(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, result, stored)
+		})
+	}
+}
+
+func TestWaitForRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		advance   func(ctx context.Context, db datastore.Datastore) error
+		wantBegun bool
+	}{
+		{"next round", func(ctx context.Context, db datastore.Datastore) error {
+			return series.NextRound(ctx, db, 2, gamephase.Finished, roster)
+		}, true},
+		{"series over", func(ctx context.Context, db datastore.Datastore) error {
+			_, err := series.StoreResult(ctx, db, series.Result{Rounds: 1, Winner: 0})
+			return err
+		}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Finished)))
+
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				assert.NoError(t, tc.advance(ctx, fakeDatastore))
+			}()
+
+			// execute
+			begun := series.WaitForRound(ctx, zap.NewNop(), fakeDatastore, 2, 5*time.Millisecond)
+
+			// verify
+			assert.Equal(t, tc.wantBegun, begun)
+		})
+	}
+}
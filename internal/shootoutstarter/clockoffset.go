@@ -0,0 +1,47 @@
+package shootoutstarter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"wildwest/internal/datastore"
+)
+
+// ClockOffsetKeyPrefix prefixes the keys holding how far the cowboys' clocks are ahead of the controller's
+const ClockOffsetKeyPrefix = "clock-offset-"
+
+// ClockOffsetKey returns the datastore key holding the clock offset of a cowboy, e.g. clock-offset-1
+func ClockOffsetKey(id int) string {
+	return ClockOffsetKeyPrefix + strconv.Itoa(id)
+}
+
+// StoreClockOffset stores how far a cowboy's clock is ahead of the controller's, so that the cowboy corrects start
+// times it reads from the datastore like pushed ones
+func StoreClockOffset(ctx context.Context, db datastore.Datastore, id int, offset time.Duration) error {
+	if err := db.Put(ctx, ClockOffsetKey(id), offset.String()); err != nil {
+		return fmt.Errorf("store clock offset: %w", err)
+	}
+
+	return nil
+}
+
+// ClockOffset returns how far a cowboy's clock is ahead of the controller's, zero if it wasn't estimated
+func ClockOffset(ctx context.Context, db datastore.Datastore, id int) (time.Duration, error) {
+	value, err := db.Get(ctx, ClockOffsetKey(id))
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("get clock offset: %w", err)
+	}
+
+	offset, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse clock offset %q: %w", value, err)
+	}
+
+	return offset, nil
+}
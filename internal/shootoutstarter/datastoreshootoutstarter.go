@@ -0,0 +1,159 @@
+package shootoutstarter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wildwest/internal/datastore"
+
+	"go.uber.org/zap"
+)
+
+// StartAtKey holds the shootout start time when cowboys pull it from the datastore
+const StartAtKey = "start-at"
+
+// StartAckKeyPrefix prefixes the keys acknowledging the start time
+const StartAckKeyPrefix = "start-ack-"
+
+// StartAckKey returns the datastore key acknowledging that a cowboy read the start time, e.g. start-ack-1
+func StartAckKey(id int) string {
+	return StartAckKeyPrefix + strconv.Itoa(id)
+}
+
+// FormatStartAt formats a start time as stored at StartAtKey
+func FormatStartAt(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// DatastoreShootoutStarter reads the shootout start time from the datastore, so that cowboys which restart or join
+// late learn it without the controller reaching them
+type DatastoreShootoutStarter struct {
+	logger       *zap.Logger
+	id           int
+	db           datastore.Datastore
+	pollInterval time.Duration
+}
+
+var _ ShootoutStarter = (*DatastoreShootoutStarter)(nil)
+
+func NewDatastore(logger *zap.Logger, id int, db datastore.Datastore, pollInterval time.Duration) *DatastoreShootoutStarter {
+	return &DatastoreShootoutStarter{
+		logger:       logger,
+		id:           id,
+		db:           db,
+		pollInterval: pollInterval,
+	}
+}
+
+// ReceiveShootoutTime ignores pushed start times, the datastore is the only source of the start time
+func (ss *DatastoreShootoutStarter) ReceiveShootoutTime(startTime time.Time, _ time.Duration) {
+	ss.logger.Debug("ignoring pushed shootout time", zap.Time("start_time", startTime))
+}
+
+// WaitForShootout polls the start time until it's set, acknowledges it and waits until it, corrected by our clock
+// offset stored by the controller
+func (ss *DatastoreShootoutStarter) WaitForShootout(ctx context.Context) error {
+	startTime, err := ss.pollStartTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := ss.acknowledge(ctx)
+		if err == nil {
+			break
+		}
+
+		ss.logger.Warn("acknowledge shootout time", zap.Error(err))
+
+		if err := sleepUntil(ctx, time.Now().Add(ss.pollInterval)); err != nil {
+			return err
+		}
+	}
+
+	// the start time is on the controller's clock, cowboys whose offset wasn't estimated are assumed to be in sync
+	clockOffset, err := ClockOffset(ctx, ss.db, ss.id)
+	if err != nil {
+		ss.logger.Warn("read clock offset", zap.Error(err))
+	}
+
+	localStartTime := startTime.Add(clockOffset)
+
+	ss.logger.Info("waiting for shootout",
+		zap.Time("start_time", startTime),
+		zap.Duration("clock_skew", clockOffset),
+		zap.Time("local_start_time", localStartTime),
+	)
+
+	return sleepUntil(ctx, localStartTime)
+}
+
+// pollStartTime reads the start time every pollInterval until it's set
+func (ss *DatastoreShootoutStarter) pollStartTime(ctx context.Context) (time.Time, error) {
+	for {
+		startTime, err := ss.readStartTime(ctx)
+		if err == nil {
+			return startTime, nil
+		}
+
+		if !errors.Is(err, datastore.ErrKeyNotFound) && ctx.Err() == nil {
+			ss.logger.Warn("read shootout time", zap.Error(err))
+		}
+
+		if err := sleepUntil(ctx, time.Now().Add(ss.pollInterval)); err != nil {
+			return time.Time{}, err
+		}
+	}
+}
+
+func (ss *DatastoreShootoutStarter) readStartTime(ctx context.Context) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	startAt, err := ss.db.Get(ctx, StartAtKey)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	startTime, err := time.Parse(time.RFC3339Nano, startAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse start time %q: %w", startAt, err)
+	}
+
+	return startTime, nil
+}
+
+func (ss *DatastoreShootoutStarter) acknowledge(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return ss.db.Put(ctx, StartAckKey(ss.id), FormatStartAt(time.Now()))
+}
+
+// AcknowledgedIDs returns the ids of the cowboys which acknowledged the start time, ignoring ids beyond replicas
+func AcknowledgedIDs(ctx context.Context, db datastore.Datastore, replicas int) (map[int]bool, error) {
+	kvs, err := db.GetPrefix(ctx, StartAckKeyPrefix)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return map[int]bool{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get start acknowledgements: %w", err)
+	}
+
+	acked := make(map[int]bool, len(kvs))
+
+	for key := range kvs {
+		id, err := strconv.Atoi(strings.TrimPrefix(key, StartAckKeyPrefix))
+		if err != nil || id < 0 || id >= replicas {
+			continue
+		}
+
+		acked[id] = true
+	}
+
+	return acked, nil
+}
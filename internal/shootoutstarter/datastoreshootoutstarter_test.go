@@ -0,0 +1,85 @@
+package shootoutstarter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/shootoutstarter"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestDatastoreShootoutStarter(t *testing.T) {
+	tests := []struct {
+		name         string
+		publishDelay time.Duration
+		startIn      time.Duration
+		clockOffset  time.Duration
+	}{
+		{"start time published before waiting", 0, 100 * time.Millisecond, 0},
+		{"start time published while waiting", 50 * time.Millisecond, 100 * time.Millisecond, 0},
+		{"joined after the start time", 0, -time.Second, 0},
+		{"clock ahead of the controller", 0, 100 * time.Millisecond, 100 * time.Millisecond},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			fakeDatastore := datastore.NewFakeClient()
+			startTime := time.Now().Add(tc.startIn)
+
+			assert.NoError(t, shootoutstarter.StoreClockOffset(context.Background(), fakeDatastore, 1, tc.clockOffset))
+
+			publish := func() {
+				assert.NoError(t, fakeDatastore.Put(context.Background(), shootoutstarter.StartAtKey, shootoutstarter.FormatStartAt(startTime)))
+			}
+
+			if tc.publishDelay == 0 {
+				publish()
+			} else {
+				time.AfterFunc(tc.publishDelay, publish)
+			}
+
+			// execute
+			err := shootoutstarter.NewDatastore(zap.NewNop(), 1, fakeDatastore, 10*time.Millisecond).WaitForShootout(context.Background())
+
+			// verify
+			assert.NoError(t, err)
+			assert.False(t, time.Now().Before(startTime.Add(tc.clockOffset)))
+
+			acked, err := shootoutstarter.AcknowledgedIDs(context.Background(), fakeDatastore, 3)
+			assert.NoError(t, err)
+			assert.Equal(t, map[int]bool{1: true}, acked)
+		})
+	}
+}
+
+func TestWaitForShootoutCanceled(t *testing.T) {
+	scheduled := shootoutstarter.New(zap.NewNop())
+	scheduled.ReceiveShootoutTime(time.Now().Add(time.Hour), 0)
+
+	tests := []struct {
+		name    string
+		starter shootoutstarter.ShootoutStarter
+	}{
+		{"start time not pushed", shootoutstarter.New(zap.NewNop())},
+		{"waiting for the pushed start time", scheduled},
+		{"start time not stored", shootoutstarter.NewDatastore(zap.NewNop(), 1, datastore.NewFakeClient(), 10*time.Millisecond)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			// execute
+			err := tc.starter.WaitForShootout(ctx)
+
+			// verify
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	}
+}
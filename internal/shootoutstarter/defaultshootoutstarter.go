@@ -1,21 +1,76 @@
 package shootoutstarter
 
-import "time"
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type shootoutTime struct {
+	startTime   time.Time
+	clockOffset time.Duration
+}
 
 type DefaultShootoutStarter struct {
-	shootoutTime chan time.Time
+	logger       *zap.Logger
+	shootoutTime chan shootoutTime
+
+	mu       sync.Mutex
+	received time.Time
 }
 
-func New() *DefaultShootoutStarter {
+var _ ShootoutStarter = (*DefaultShootoutStarter)(nil)
+
+func New(logger *zap.Logger) *DefaultShootoutStarter {
 	return &DefaultShootoutStarter{
-		shootoutTime: make(chan time.Time, 1),
+		logger:       logger,
+		shootoutTime: make(chan shootoutTime, 1),
 	}
 }
 
-func (ss *DefaultShootoutStarter) ReceiveShootoutTime(t time.Time) {
-	ss.shootoutTime <- t
+// ReceiveShootoutTime keeps the latest shootout time received. The controller re-delivers it until acknowledged, so
+// start times which aren't later than the last one received are ignored, later ones are for the next round
+func (ss *DefaultShootoutStarter) ReceiveShootoutTime(startTime time.Time, clockOffset time.Duration) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if !startTime.After(ss.received) {
+		return
+	}
+
+	ss.received = startTime
+
+	// replace a start time nobody waited for yet
+	select {
+	case <-ss.shootoutTime:
+	default:
+	}
+
+	ss.shootoutTime <- shootoutTime{
+		startTime:   startTime,
+		clockOffset: clockOffset,
+	}
 }
 
-func (ss *DefaultShootoutStarter) WaitForShootout() {
-	time.Sleep(time.Until(<-ss.shootoutTime))
+// WaitForShootout waits until the start time corrected by our clock offset
+func (ss *DefaultShootoutStarter) WaitForShootout(ctx context.Context) error {
+	var st shootoutTime
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case st = <-ss.shootoutTime:
+	}
+
+	localStartTime := st.startTime.Add(st.clockOffset)
+
+	ss.logger.Info("waiting for shootout",
+		zap.Time("start_time", st.startTime),
+		zap.Duration("clock_skew", st.clockOffset),
+		zap.Time("local_start_time", localStartTime),
+	)
+
+	return sleepUntil(ctx, localStartTime)
 }
@@ -0,0 +1,55 @@
+package shootoutstarter
+
+import (
+	"context"
+	"sync"
+)
+
+// Life holds the context of our cowboy's life in the game, it's canceled once the cowboy is killed and renewed once
+// it's revived
+type Life struct {
+	parent context.Context
+
+	mu     *sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewLife(parent context.Context) *Life {
+	ctx, cancel := context.WithCancel(parent)
+
+	return &Life{
+		parent: parent,
+		mu:     &sync.Mutex{},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context returns the context of the current life
+func (l *Life) Context() context.Context {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.ctx
+}
+
+// End cancels the context of the current life
+func (l *Life) End() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cancel()
+}
+
+// Renew begins a new life if the current one ended
+func (l *Life) Renew() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ctx.Err() == nil {
+		return
+	}
+
+	l.ctx, l.cancel = context.WithCancel(l.parent)
+}
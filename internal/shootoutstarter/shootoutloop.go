@@ -2,61 +2,267 @@ package shootoutstarter
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/healthcheck"
 	"wildwest/internal/shotlooper"
 	"wildwest/internal/utils"
+	"wildwest/internal/winner"
 
 	"go.uber.org/zap"
 )
 
+// PhaseKeyPrefix prefixes the keys holding the cowboys' persisted phases
+const PhaseKeyPrefix = "phase-"
+
+// PhaseKey returns the datastore key holding the game phase of a cowboy, e.g. phase-1
+func PhaseKey(id int) string {
+	return PhaseKeyPrefix + strconv.Itoa(id)
+}
+
 type Config struct {
 	ID     int
 	Cowboy utils.Cowboy
+	// Replicas is the number of cowboys in the game, the winner is declared once all the others are dead
+	Replicas int
 
 	DB              datastore.Datastore
 	ShooterHandler  shotlooper.ShotLooper
 	ShootoutManager ShootoutStarter
+	// StartMode tells whether the controller pushes the start time to the shootout manager or it's pulled from the
+	// datastore by it
+	StartMode string
 
 	Ready func()
+	// Health reports the game phase, optional
+	Health *healthcheck.Reporter
 }
 
+// Start plays the shootout and returns true if we won. Our phase is persisted in the datastore, so that after a
+// restart we resume from where we were: waiting for the start time, shooting, dead or the winner
 func Start(ctx context.Context, logger *zap.Logger, cfg *Config) bool {
 	dbCtx, dbCtxCancel := context.WithTimeout(ctx, time.Minute)
 	defer dbCtxCancel()
 
-	health, err := cfg.DB.Get(dbCtx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID))
-	// if we didn't find the health value already in the database
+	phase, err := resumePhase(dbCtx, cfg)
 	if err != nil {
-		logger.Debug("didn't find health already in the database")
+		logger.Fatal("get phase", zap.Error(err))
+	}
+
+	logger = logger.With(zap.String("resumed_phase", string(phase)))
 
-		// initialize health in etcd
-		err := cfg.DB.Put(dbCtx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID), strconv.Itoa(int(cfg.Cowboy.Health)))
-		if err != nil {
-			logger.Fatal("set initial health value", zap.Error(err))
+	switch phase {
+	case healthcheck.PhaseDead:
+		logger.Debug("found phase already in the database, but we're already dead")
+		setPhase(logger, cfg, healthcheck.PhaseDead)
+		return false
+	case healthcheck.PhaseWinner:
+		logger.Debug("found phase already in the database, we already won")
+		setPhase(logger, cfg, healthcheck.PhaseWinner)
+
+		// we may have restarted before the game finished
+		if _, err := declareWinner(logger, cfg); err != nil {
+			logger.Error("confirm win", zap.Error(err))
 		}
 
+		return true
+	case healthcheck.PhaseWaiting:
+		// initialize health in etcd unless we restarted after doing so
+		if _, err := cfg.DB.Get(dbCtx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID)); errors.Is(err, datastore.ErrKeyNotFound) {
+			err := cfg.DB.Put(dbCtx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID), strconv.Itoa(int(cfg.Cowboy.Health)))
+			if err != nil {
+				logger.Fatal("set initial health value", zap.Error(err))
+			}
+		} else if err != nil {
+			logger.Fatal("get health", zap.Error(err))
+		}
+
+		setPhase(logger, cfg, healthcheck.PhaseWaiting)
+
 		// start readiness server
 		go cfg.Ready()
 
 		logger.Info("waiting to begin shootout...")
 
-		// wait until shootout beginning
-		cfg.ShootoutManager.WaitForShootout()
+		// wait until shootout beginning, we keep waiting after restarting if we're shut down meanwhile
+		if err := waitForShootout(ctx, logger, cfg); err != nil {
+			logger.Info("stopped waiting for shootout", zap.Error(err))
+			return false
+		}
+
 		logger.Info("beginning shootout!")
+	default:
+		// the shootout already began before we restarted
+		go cfg.Ready()
 	}
 
-	if health == "0" {
+	// we may have been killed before persisting our phase, or forfeited while waiting
+	if health, err := cfg.DB.Get(dbCtx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID)); err == nil && health == "0" {
 		logger.Debug("found health already in the database, but we're already dead")
+		setPhase(logger, cfg, healthcheck.PhaseDead)
+		return false
+	}
+
+	setPhase(logger, cfg, healthcheck.PhaseShooting)
+
+	// the shot loop stops once we think we won, but only the winner record makes it so
+	for cfg.ShooterHandler.StartShootingLoop(ctx) {
+		shootOn, err := declareWinner(logger, cfg)
+		if err == nil {
+			setPhase(logger, cfg, healthcheck.PhaseWinner)
+			return true
+		}
+
+		if !shootOn {
+			logger.Info("lost the shootout", zap.Error(err))
+			break
+		}
+
+		logger.Info("not the winner yet, shooting on", zap.Error(err))
+	}
+
+	// the loop also stops when we're shut down, then we keep our phase to resume after restarting
+	healthCtx, healthCtxCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer healthCtxCancel()
+
+	if health, err := cfg.DB.Get(healthCtx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID)); err == nil && health != "0" {
+		logger.Info("shootout interrupted", zap.String("health", health))
 		return false
 	}
 
-	// if process restarted since health was found
+	setPhase(logger, cfg, healthcheck.PhaseDead)
+
+	return false
+}
+
+// resumePhase returns the persisted phase of our cowboy, cowboys without a persisted phase either never started or
+// are from before phases were persisted and resume from their health
+func resumePhase(ctx context.Context, cfg *Config) (healthcheck.Phase, error) {
+	phase, err := cfg.DB.Get(ctx, PhaseKey(cfg.ID))
 	if err == nil {
-		// start readiness server
-		go cfg.Ready()
+		return healthcheck.Phase(phase), nil
+	}
+
+	if !errors.Is(err, datastore.ErrKeyNotFound) {
+		return "", err
+	}
+
+	health, err := cfg.DB.Get(ctx, utils.CowboyKeyPrefix+strconv.Itoa(cfg.ID))
+	switch {
+	case errors.Is(err, datastore.ErrKeyNotFound):
+		return healthcheck.PhaseWaiting, nil
+	case err != nil:
+		return "", err
+	case health == "0":
+		return healthcheck.PhaseDead, nil
+	default:
+		return healthcheck.PhaseShooting, nil
+	}
+}
+
+// waitForShootout waits until the start time through the shootout manager. A pushed start time isn't pushed again
+// once the controller got our acknowledgement, so if the game's countdown began before we waited, e.g. because we
+// restarted, the shootout manager is handed the start time stored by the controller, corrected by our clock offset it
+// stored. It then ignores the same start time if it's still pushed to us, instead of keeping it for the next round
+func waitForShootout(ctx context.Context, logger *zap.Logger, cfg *Config) error {
+	if cfg.StartMode == utils.StartModePull {
+		return cfg.ShootoutManager.WaitForShootout(ctx)
+	}
+
+	startTime, err := broadcastStartTime(ctx, cfg.DB)
+	if err != nil {
+		logger.Warn("read stored start time", zap.Error(err))
+	}
+
+	if err != nil || startTime.IsZero() {
+		return cfg.ShootoutManager.WaitForShootout(ctx)
+	}
+
+	clockOffset, err := ClockOffset(ctx, cfg.DB, cfg.ID)
+	if err != nil {
+		logger.Warn("read clock offset", zap.Error(err))
+	}
+
+	logger.Info("found stored shootout time", zap.Time("start_time", startTime))
+
+	cfg.ShootoutManager.ReceiveShootoutTime(startTime, clockOffset)
+
+	return cfg.ShootoutManager.WaitForShootout(ctx)
+}
+
+// broadcastStartTime returns the start time stored by the controller if it was broadcast already, zero if the game is
+// registering the cowboys or the start time isn't stored yet
+func broadcastStartTime(ctx context.Context, db datastore.Datastore) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	phase, err := gamephase.Get(ctx, db)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if phase == gamephase.Registering {
+		return time.Time{}, nil
+	}
+
+	startAt, err := db.Get(ctx, StartAtKey)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get start time: %w", err)
+	}
+
+	startTime, err := time.Parse(time.RFC3339Nano, startAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse start time %q: %w", startAt, err)
+	}
+
+	return startTime, nil
+}
+
+// setPhase persists and reports our phase, it's persisted even if ctx was canceled because we were killed
+func setPhase(logger *zap.Logger, cfg *Config, phase healthcheck.Phase) {
+	cfg.Health.SetPhase(phase)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := cfg.DB.Put(ctx, PhaseKey(cfg.ID), string(phase)); err != nil {
+		logger.Error("persist phase", zap.String("phase", string(phase)), zap.Error(err))
+	}
+}
+
+// declareWinner records us as the winner and finishes the game. If it fails, shootOn reports whether we may still win,
+// e.g. because our view of the others' health was out of date or the game is paused
+func declareWinner(logger *zap.Logger, cfg *Config) (shootOn bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err := winner.Declare(ctx, cfg.DB, cfg.Replicas, cfg.ID, cfg.Cowboy.Name)
+	if err != nil {
+		return !errors.Is(err, winner.ErrAlreadyDeclared), err
+	}
+
+	logger.Info("declared the winner", zap.Int("health", record.Health), zap.Time("declared_at", record.DeclaredAt))
+
+	return false, nil
+}
+
+// Forfeit kills a cowboy and persists it as dead, so that it's neither targeted nor resumes shooting after restarting
+func Forfeit(ctx context.Context, db datastore.Datastore, id int) error {
+	if err := db.Put(ctx, utils.CowboyKeyPrefix+strconv.Itoa(id), "0"); err != nil {
+		return fmt.Errorf("set health: %w", err)
+	}
+
+	if err := db.Put(ctx, PhaseKey(id), string(healthcheck.PhaseDead)); err != nil {
+		return fmt.Errorf("set phase: %w", err)
 	}
 
-	return cfg.ShooterHandler.StartShootingLoop(ctx)
+	return nil
 }
@@ -0,0 +1,236 @@
+package shootoutstarter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/healthcheck"
+	"wildwest/internal/shootoutstarter"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeShotLooper struct {
+	calls    int
+	isWinner bool
+	// kill is called while shooting, optional
+	kill func()
+}
+
+func (fsl *fakeShotLooper) StartShootingLoop(context.Context) bool {
+	fsl.calls++
+
+	if fsl.kill != nil {
+		fsl.kill()
+	}
+
+	return fsl.isWinner
+}
+
+type fakeShootoutStarter struct {
+	waits int
+	// received is the local start time received, if any
+	received time.Time
+}
+
+func (fss *fakeShootoutStarter) ReceiveShootoutTime(startTime time.Time, clockOffset time.Duration) {
+	fss.received = startTime.Add(clockOffset)
+}
+
+func (fss *fakeShootoutStarter) WaitForShootout(context.Context) error {
+	fss.waits++
+
+	time.Sleep(time.Until(fss.received))
+
+	return nil
+}
+
+func TestStartRestart(t *testing.T) {
+	tests := []struct {
+		name       string
+		phase      string
+		health     string
+		startIn    time.Duration
+		killed     bool
+		wantWinner bool
+		wantWaits  int
+		wantLoops  int
+		wantPhase  healthcheck.Phase
+		wantHealth string
+	}{
+		{"fresh start", "", "", 0, false, true, 1, 1, healthcheck.PhaseWinner, "10"},
+		{"killed after fresh start", "", "", 0, true, false, 1, 1, healthcheck.PhaseDead, "0"},
+		{"restart before start time is stored", "waiting", "10", 0, false, true, 1, 1, healthcheck.PhaseWinner, "10"},
+		{"restart before start time", "waiting", "10", 100 * time.Millisecond, false, true, 1, 1, healthcheck.PhaseWinner, "10"},
+		{"restart while running", "shooting", "6", -time.Second, false, true, 0, 1, healthcheck.PhaseWinner, "6"},
+		{"shut down while running", "shooting", "6", -time.Second, false, false, 0, 1, healthcheck.PhaseShooting, "6"},
+		{"restart after being killed while running", "shooting", "0", -time.Second, false, false, 0, 0, healthcheck.PhaseDead, "0"},
+		{"restart when dead", "dead", "0", -time.Second, false, false, 0, 0, healthcheck.PhaseDead, "0"},
+		{"restart when finished", "winner", "4", -time.Second, false, true, 0, 0, healthcheck.PhaseWinner, "4"},
+		{"restart without persisted phase", "", "6", -time.Second, false, true, 0, 1, healthcheck.PhaseWinner, "6"},
+		{"forfeited while waiting", "waiting", "0", 0, false, false, 1, 0, healthcheck.PhaseDead, "0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+			if tc.phase != "" {
+				assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(1), tc.phase))
+			}
+
+			if tc.health != "" {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", tc.health))
+			}
+
+			startTime := time.Now().Add(tc.startIn)
+			if tc.startIn != 0 {
+				assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.StartAtKey, shootoutstarter.FormatStartAt(startTime)))
+			}
+
+			shotLooper := &fakeShotLooper{isWinner: tc.wantWinner}
+			if tc.killed {
+				shotLooper.kill = func() {
+					assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "0"))
+				}
+			}
+			shootoutManager := &fakeShootoutStarter{}
+
+			// execute
+			isWinner := shootoutstarter.Start(ctx, zap.NewNop(), &shootoutstarter.Config{
+				ID:              1,
+				Cowboy:          utils.Cowboy{Name: "John", Health: 10, Damage: 1},
+				DB:              fakeDatastore,
+				ShooterHandler:  shotLooper,
+				ShootoutManager: shootoutManager,
+				Ready:           func() {},
+			})
+
+			// verify
+			assert.Equal(t, tc.wantWinner, isWinner)
+			assert.Equal(t, tc.wantWaits, shootoutManager.waits)
+			assert.Equal(t, tc.wantLoops, shotLooper.calls)
+
+			if tc.wantLoops > 0 {
+				assert.False(t, time.Now().Before(startTime))
+			}
+
+			phase, err := fakeDatastore.Get(ctx, shootoutstarter.PhaseKey(1))
+			assert.NoError(t, err)
+			assert.Equal(t, string(tc.wantPhase), phase)
+
+			health, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHealth, health)
+
+			// the winner finishes the game
+			wantGamePhase := gamephase.Running
+			if tc.wantWinner {
+				wantGamePhase = gamephase.Finished
+			}
+
+			gamePhase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, wantGamePhase, gamePhase)
+		})
+	}
+}
+
+func TestStartWaitsForShootout(t *testing.T) {
+	tests := []struct {
+		name         string
+		startMode    string
+		gamePhase    gamephase.Phase
+		stored       bool
+		clockOffset  time.Duration
+		wantReceived bool
+	}{
+		{"pushed while registering", utils.StartModePush, gamephase.Registering, false, 0, false},
+		{"pushed before restarting in the countdown", utils.StartModePush, gamephase.Countdown, true, 0, true},
+		{"pushed before restarting with our clock ahead", utils.StartModePush, gamephase.Countdown, true, 100 * time.Millisecond, true},
+		{"pushed in the countdown before it's stored", utils.StartModePush, gamephase.Countdown, false, 0, false},
+		{"pulled in the countdown", utils.StartModePull, gamephase.Countdown, true, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.gamePhase)))
+			assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(1), string(healthcheck.PhaseWaiting)))
+
+			startTime := time.Now().Add(50 * time.Millisecond)
+			if tc.stored {
+				assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.StartAtKey, shootoutstarter.FormatStartAt(startTime)))
+				assert.NoError(t, shootoutstarter.StoreClockOffset(ctx, fakeDatastore, 1, tc.clockOffset))
+			}
+
+			shootoutManager := &fakeShootoutStarter{}
+
+			// execute
+			shootoutstarter.Start(ctx, zap.NewNop(), &shootoutstarter.Config{
+				ID:              1,
+				Cowboy:          utils.Cowboy{Name: "John", Health: 10, Damage: 1},
+				DB:              fakeDatastore,
+				ShooterHandler:  &fakeShotLooper{},
+				ShootoutManager: shootoutManager,
+				StartMode:       tc.startMode,
+				Ready:           func() {},
+			})
+
+			// verify, the stored start time is handed to the shootout manager
+			assert.Equal(t, 1, shootoutManager.waits)
+			assert.Equal(t, tc.wantReceived, !shootoutManager.received.IsZero())
+
+			if tc.wantReceived {
+				assert.False(t, time.Now().Before(startTime.Add(tc.clockOffset)))
+			}
+
+			// only the pull shootout manager acknowledges the start time
+			_, err := fakeDatastore.Get(ctx, shootoutstarter.StartAckKey(1))
+			assert.ErrorIs(t, err, datastore.ErrKeyNotFound)
+		})
+	}
+}
+
+func TestStartIgnoresRedeliveredShootoutTime(t *testing.T) {
+	// setup, we restarted in the countdown and find the start time stored
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Countdown)))
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.PhaseKey(1), string(healthcheck.PhaseWaiting)))
+
+	startTime := time.Now().Add(50 * time.Millisecond)
+	assert.NoError(t, fakeDatastore.Put(ctx, shootoutstarter.StartAtKey, shootoutstarter.FormatStartAt(startTime)))
+
+	shootoutManager := shootoutstarter.New(zap.NewNop())
+
+	shootoutstarter.Start(ctx, zap.NewNop(), &shootoutstarter.Config{
+		ID:              1,
+		Cowboy:          utils.Cowboy{Name: "John", Health: 10, Damage: 1},
+		DB:              fakeDatastore,
+		ShooterHandler:  &fakeShotLooper{},
+		ShootoutManager: shootoutManager,
+		StartMode:       utils.StartModePush,
+		Ready:           func() {},
+	})
+
+	// execute, the controller still delivers the start time of the round that began
+	shootoutManager.ReceiveShootoutTime(startTime, 0)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	err := shootoutManager.WaitForShootout(waitCtx)
+
+	// verify, the next round waits for its own start time
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -1,10 +1,26 @@
 package shootoutstarter
 
 import (
+	"context"
 	"time"
 )
 
 type ShootoutStarter interface {
-	ReceiveShootoutTime(time.Time)
-	WaitForShootout()
+	// ReceiveShootoutTime receives the start time on the controller's clock and how far our clock is ahead of it
+	ReceiveShootoutTime(startTime time.Time, clockOffset time.Duration)
+	// WaitForShootout waits until the start time, it returns ctx.Err() if ctx is done first
+	WaitForShootout(ctx context.Context) error
+}
+
+// sleepUntil waits until t, it returns ctx.Err() if ctx is done first
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -0,0 +1,146 @@
+package shotauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"wildwest/internal/utils"
+)
+
+const (
+	ErrNoPrivateKey     = utils.ConstError("no PEM private key found")
+	ErrNotEd25519Key    = utils.ConstError("private key is not an Ed25519 key")
+	ErrInvalidPublicKey = utils.ConstError("invalid Ed25519 public key")
+)
+
+const (
+	PublicKeysFileName   = "shot-public-keys.json"
+	privateKeyFileSuffix = "-shot-key.pem"
+)
+
+// Config holds the key paths, paths are expanded with environment variables (e.g. ${HOSTNAME})
+type Config struct {
+	Enabled        bool
+	KeyFile        string
+	PublicKeysFile string
+	MaxAge         time.Duration
+}
+
+// Load reads our private key and the public keys of all cowboys,
+// returns a nil Signer and Verifier if shot signing is disabled
+func Load(cfg Config, id int) (*Signer, *Verifier, error) {
+	if !cfg.Enabled {
+		return nil, nil, nil
+	}
+
+	keyPEM, err := os.ReadFile(os.ExpandEnv(cfg.KeyFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read shot key: %w", err)
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicKeysJSON, err := os.ReadFile(os.ExpandEnv(cfg.PublicKeysFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read shot public keys: %w", err)
+	}
+
+	publicKeys, err := parsePublicKeys(publicKeysJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewSigner(key), NewVerifier(id, publicKeys, cfg.MaxAge), nil
+}
+
+func parsePrivateKey(keyPEM []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, ErrNoPrivateKey
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse shot key: %w", err)
+	}
+
+	ed25519Key, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, ErrNotEd25519Key
+	}
+
+	return ed25519Key, nil
+}
+
+// parsePublicKeys parses a JSON array of public keys, the n-th key belongs to cowboy n
+func parsePublicKeys(publicKeysJSON []byte) ([]ed25519.PublicKey, error) {
+	var encoded [][]byte
+	if err := json.Unmarshal(publicKeysJSON, &encoded); err != nil {
+		return nil, fmt.Errorf("unmarshal shot public keys: %w", err)
+	}
+
+	publicKeys := make([]ed25519.PublicKey, 0, len(encoded))
+
+	for id, key := range encoded {
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w for cowboy %d", ErrInvalidPublicKey, id)
+		}
+
+		publicKeys = append(publicKeys, ed25519.PublicKey(key))
+	}
+
+	return publicKeys, nil
+}
+
+// GenerateAll writes a private key for every cowboy and the public keys of all cowboys into dir.
+// Private keys are named after the StatefulSet hostname, e.g. cowboy-0-shot-key.pem
+func GenerateAll(dir string, replicas int, cowboyAppName string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	publicKeys := make([][]byte, 0, replicas)
+
+	for id := 0; id < replicas; id++ {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return fmt.Errorf("generate shot key: %w", err)
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return fmt.Errorf("marshal shot key: %w", err)
+		}
+
+		name := fmt.Sprintf("%s-%d%s", cowboyAppName, id, privateKeyFileSuffix)
+		if err := writeFile(dir, name, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})); err != nil {
+			return err
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	publicKeysJSON, err := json.Marshal(publicKeys)
+	if err != nil {
+		return fmt.Errorf("marshal shot public keys: %w", err)
+	}
+
+	return writeFile(dir, PublicKeysFileName, publicKeysJSON)
+}
+
+func writeFile(dir string, name string, data []byte) error {
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package shotauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+	"wildwest/internal/utils"
+)
+
+const (
+	ErrUnsigned         = utils.ConstError("shot is not signed")
+	ErrUnknownSigner    = utils.ConstError("no public key for shooter")
+	ErrInvalidSignature = utils.ConstError("invalid shot signature")
+	ErrStaleShot        = utils.ConstError("shot is too old or from the future")
+	ErrReplayedShot     = utils.ConstError("shot nonce was already used")
+)
+
+const nonceLen = 16
+
+// Signature authenticates a single shot
+type Signature struct {
+	Nonce     string
+	SentAt    int64
+	Signature []byte
+}
+
+// payload returns the signed bytes of a shot, the target is part of it so a shot can't be replayed to another cowboy
+func payload(to int, from int64, damage int64, nonce string, sentAt int64) []byte {
+	b := make([]byte, 0, 32+len(nonce))
+	b = binary.BigEndian.AppendUint64(b, uint64(to))
+	b = binary.BigEndian.AppendUint64(b, uint64(from))
+	b = binary.BigEndian.AppendUint64(b, uint64(damage))
+	b = binary.BigEndian.AppendUint64(b, uint64(sentAt))
+
+	return append(b, nonce...)
+}
+
+// Signer signs our shots with our private key, a nil Signer means shot signing is disabled
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{
+		key: key,
+	}
+}
+
+// Sign signs a shot at the given cowboy, returns an empty signature if signing is disabled
+func (s *Signer) Sign(to int, from int64, damage int64) (Signature, error) {
+	if s == nil {
+		return Signature{}, nil
+	}
+
+	nonce := make([]byte, nonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return Signature{}, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	sig := Signature{
+		Nonce:  hex.EncodeToString(nonce),
+		SentAt: time.Now().UnixNano(),
+	}
+	sig.Signature = ed25519.Sign(s.key, payload(to, from, damage, sig.Nonce, sig.SentAt))
+
+	return sig, nil
+}
+
+// Verifier checks the shots received by cowboy id, a nil Verifier accepts every shot
+type Verifier struct {
+	id         int
+	publicKeys []ed25519.PublicKey
+	maxAge     time.Duration
+
+	mu *sync.Mutex
+	// seen holds the expiry of every nonce used within maxAge, keyed by shooter and nonce
+	seen      map[string]time.Time
+	cleanedAt time.Time
+}
+
+// NewVerifier creates the verifier of the shots received by cowboy id, publicKeys[n] belongs to cowboy n.
+// Shots older than maxAge are rejected, so nonces only need to be remembered for maxAge
+func NewVerifier(id int, publicKeys []ed25519.PublicKey, maxAge time.Duration) *Verifier {
+	return &Verifier{
+		id:         id,
+		publicKeys: publicKeys,
+		maxAge:     maxAge,
+		mu:         &sync.Mutex{},
+		seen:       make(map[string]time.Time),
+	}
+}
+
+// Verify checks that the shot was signed by the shooter and that its nonce wasn't used before
+func (v *Verifier) Verify(from int64, damage int64, sig Signature) error {
+	if v == nil {
+		return nil
+	}
+
+	if sig.Nonce == "" || len(sig.Signature) == 0 {
+		return ErrUnsigned
+	}
+
+	if from < 0 || from >= int64(len(v.publicKeys)) || v.publicKeys[from] == nil {
+		return fmt.Errorf("%w %d", ErrUnknownSigner, from)
+	}
+
+	if !ed25519.Verify(v.publicKeys[from], payload(v.id, from, damage, sig.Nonce, sig.SentAt), sig.Signature) {
+		return ErrInvalidSignature
+	}
+
+	now := time.Now()
+
+	sentAt := time.Unix(0, sig.SentAt)
+	if now.Sub(sentAt) > v.maxAge || sentAt.Sub(now) > v.maxAge {
+		return ErrStaleShot
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// forget expired nonces every maxAge
+	if now.Sub(v.cleanedAt) > v.maxAge {
+		for key, expiry := range v.seen {
+			if now.After(expiry) {
+				delete(v.seen, key)
+			}
+		}
+
+		v.cleanedAt = now
+	}
+
+	key := fmt.Sprintf("%d/%s", from, sig.Nonce)
+	if _, ok := v.seen[key]; ok {
+		return ErrReplayedShot
+	}
+
+	// the shot is accepted until maxAge after it was sent, the nonce must be remembered at least as long
+	v.seen[key] = sentAt.Add(v.maxAge)
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package shotauth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+	"time"
+	"wildwest/internal/shotauth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	publicKeys := make([]ed25519.PublicKey, 0, 3)
+	signers := make([]*shotauth.Signer, 0, 3)
+
+	for i := 0; i < 3; i++ {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+
+		publicKeys = append(publicKeys, publicKey)
+		signers = append(signers, shotauth.NewSigner(privateKey))
+	}
+
+	sign := func(to int, from int64, damage int64) shotauth.Signature {
+		sig, err := signers[from].Sign(to, from, damage)
+		assert.NoError(t, err)
+
+		return sig
+	}
+
+	backdated := sign(1, 2, 3)
+	backdated.SentAt = time.Now().Add(-time.Hour).UnixNano()
+
+	tests := []struct {
+		name   string
+		from   int64
+		damage int64
+		sig    shotauth.Signature
+		err    error
+	}{
+		{"valid", 2, 3, sign(1, 2, 3), nil},
+		{"unsigned", 2, 3, shotauth.Signature{}, shotauth.ErrUnsigned},
+		{"spoofed shooter", 0, 3, sign(1, 2, 3), shotauth.ErrInvalidSignature},
+		{"tampered damage", 2, 30, sign(1, 2, 3), shotauth.ErrInvalidSignature},
+		{"other target", 2, 3, sign(0, 2, 3), shotauth.ErrInvalidSignature},
+		{"unknown shooter", 5, 3, sign(1, 2, 3), shotauth.ErrUnknownSigner},
+		{"tampered send time", 2, 3, backdated, shotauth.ErrInvalidSignature},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier := shotauth.NewVerifier(1, publicKeys, time.Minute)
+			assert.ErrorIs(t, verifier.Verify(tc.from, tc.damage, tc.sig), tc.err)
+		})
+	}
+}
+
+func TestVerifyReplay(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	verifier := shotauth.NewVerifier(1, []ed25519.PublicKey{publicKey}, time.Minute)
+
+	sig, err := shotauth.NewSigner(privateKey).Sign(1, 0, 3)
+	assert.NoError(t, err)
+
+	assert.NoError(t, verifier.Verify(0, 3, sig))
+	assert.ErrorIs(t, verifier.Verify(0, 3, sig), shotauth.ErrReplayedShot)
+}
+
+func TestDisabled(t *testing.T) {
+	signer, verifier, err := shotauth.Load(shotauth.Config{Enabled: false}, 0)
+	assert.NoError(t, err)
+
+	sig, err := signer.Sign(1, 0, 3)
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.Verify(0, 3, sig))
+}
+
+func TestGenerateAllAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, shotauth.GenerateAll(dir, 2, "cowboy"))
+
+	load := func(id int, name string) (*shotauth.Signer, *shotauth.Verifier) {
+		signer, verifier, err := shotauth.Load(shotauth.Config{
+			Enabled:        true,
+			KeyFile:        filepath.Join(dir, name+"-shot-key.pem"),
+			PublicKeysFile: filepath.Join(dir, shotauth.PublicKeysFileName),
+			MaxAge:         time.Minute,
+		}, id)
+		assert.NoError(t, err)
+
+		return signer, verifier
+	}
+
+	signer, _ := load(0, "cowboy-0")
+	_, verifier := load(1, "cowboy-1")
+
+	sig, err := signer.Sign(1, 0, 3)
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.Verify(0, 3, sig))
+}
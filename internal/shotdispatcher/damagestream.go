@@ -0,0 +1,186 @@
+package shotdispatcher
+
+import (
+	"context"
+	"sync"
+	"wildwest/internal/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
+
+const ErrStreamClosed = utils.ConstError("damage stream closed")
+
+// damageStream sends shots to one peer over a single StreamDamage stream and matches acknowledgements to shots.
+// At most window shots are in flight, further shots wait for an acknowledgement
+type damageStream struct {
+	stream damagev2pb.DamageService_StreamDamageClient
+	cancel context.CancelFunc
+
+	window chan struct{}
+	sendMu *sync.Mutex
+
+	mu      *sync.Mutex
+	nextSeq uint64
+	pending map[uint64]chan ackResult
+	err     error
+	done    chan struct{}
+}
+
+// ackResult is the outcome of a shot sent over the stream
+type ackResult struct {
+	resp *damagev2pb.DamageResponse
+	err  error
+}
+
+// openDamageStream opens a stream to the peer, the stream lives until close is called or it breaks
+func openDamageStream(client damagev2pb.DamageServiceClient, window int) (*damageStream, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := client.StreamDamage(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ds := &damageStream{
+		stream:  stream,
+		cancel:  cancel,
+		window:  make(chan struct{}, window),
+		sendMu:  &sync.Mutex{},
+		mu:      &sync.Mutex{},
+		pending: make(map[uint64]chan ackResult),
+		done:    make(chan struct{}),
+	}
+
+	go ds.receiveAcks()
+
+	return ds, nil
+}
+
+// shoot sends a shot and waits for its acknowledgement
+func (ds *damageStream) shoot(ctx context.Context, req *damagev2pb.DamageRequest) (*damagev2pb.DamageResponse, error) {
+	// wait for room in the window
+	select {
+	case ds.window <- struct{}{}:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-ds.done:
+		return nil, ds.closeErr()
+	}
+	defer func() { <-ds.window }()
+
+	ack := make(chan ackResult, 1)
+
+	ds.mu.Lock()
+	if ds.err != nil {
+		ds.mu.Unlock()
+		return nil, ds.err
+	}
+
+	ds.nextSeq++
+	seq := ds.nextSeq
+	ds.pending[seq] = ack
+	ds.mu.Unlock()
+
+	defer func() {
+		ds.mu.Lock()
+		delete(ds.pending, seq)
+		ds.mu.Unlock()
+	}()
+
+	ds.sendMu.Lock()
+	err := ds.stream.Send(&damagev2pb.DamageRequest{
+		From:      req.GetFrom(),
+		Damage:    req.GetDamage(),
+		Seq:       seq,
+		Nonce:     req.GetNonce(),
+		SentAt:    req.GetSentAt(),
+		Signature: req.GetSignature(),
+	})
+	ds.sendMu.Unlock()
+
+	if err != nil {
+		// the real error is returned by Recv
+		<-ds.done
+		return nil, ds.closeErr()
+	}
+
+	select {
+	case result := <-ack:
+		return result.resp, result.err
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// receiveAcks delivers acknowledgements to the waiting shots until the stream breaks
+func (ds *damageStream) receiveAcks() {
+	for {
+		ack, err := ds.stream.Recv()
+		if err != nil {
+			ds.fail(err)
+			return
+		}
+
+		result := ackResult{resp: ack.GetResponse()}
+		if ack.GetCode() != int32(codes.OK) {
+			result = ackResult{err: status.Error(codes.Code(ack.GetCode()), ack.GetMessage())}
+		}
+
+		ds.mu.Lock()
+		if waiting, ok := ds.pending[ack.GetSeq()]; ok {
+			delete(ds.pending, ack.GetSeq())
+			waiting <- result
+		}
+		ds.mu.Unlock()
+	}
+}
+
+// fail marks the stream as broken and fails all waiting shots
+func (ds *damageStream) fail(err error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	ds.err = err
+
+	for seq, waiting := range ds.pending {
+		delete(ds.pending, seq)
+		waiting <- ackResult{err: err}
+	}
+
+	close(ds.done)
+}
+
+// closeErr returns the error the stream broke with
+func (ds *damageStream) closeErr() error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.err == nil {
+		return ErrStreamClosed
+	}
+
+	return ds.err
+}
+
+// broken returns whether the stream can't carry shots anymore
+func (ds *damageStream) broken() bool {
+	select {
+	case <-ds.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close ends the stream
+func (ds *damageStream) close() {
+	ds.sendMu.Lock()
+	_ = ds.stream.CloseSend()
+	ds.sendMu.Unlock()
+
+	ds.cancel()
+}
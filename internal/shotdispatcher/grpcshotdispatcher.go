@@ -4,56 +4,83 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/resolver"
+	"wildwest/internal/shotauth"
+	"wildwest/internal/tlsconfig"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
 )
 
 type CowboyClient struct {
-	client damagepb.DamageServiceClient
+	client   damagepb.DamageServiceClient
+	clientV2 damagev2pb.DamageServiceClient
+	// interceptors are the dispatcher's interceptors followed by the one signing our shots at the cowboy
+	interceptors []grpc.UnaryClientInterceptor
+
+	// stream is nil until the first shot, legacy is set once the peer turned out to serve only the v1 API
+	stream *damageStream
+	legacy bool
 }
 
 type GRPCShotDispatcher struct {
 	logger        *zap.Logger
-	podName       string
-	serviceName   string
-	grpcPort      int
+	resolver      resolver.Resolver
+	creds         *tlsconfig.Credentials
+	signer        *shotauth.Signer
+	interceptors  []grpc.UnaryClientInterceptor
+	streamWindow  int
 	cowboyClients map[int]*CowboyClient
 }
 
 var _ ShotDispatcher = (*GRPCShotDispatcher)(nil)
 
-func NewGRPC(logger *zap.Logger, podName string, serviceName string, grpcPort int) *GRPCShotDispatcher {
+// NewGRPC creates a gRPC shot dispatcher, shots are sent over a v2 stream per peer with at most streamWindow
+// unacknowledged shots, or with unary calls if streamWindow is 0. Peers serving only the v1 API get v1 unary calls
+func NewGRPC(logger *zap.Logger, resolver resolver.Resolver, creds *tlsconfig.Credentials, signer *shotauth.Signer, interceptors []grpc.UnaryClientInterceptor, streamWindow int) *GRPCShotDispatcher {
 	return &GRPCShotDispatcher{
 		logger:        logger,
-		podName:       podName,
-		serviceName:   serviceName,
-		grpcPort:      grpcPort,
+		resolver:      resolver,
+		creds:         creds,
+		signer:        signer,
+		interceptors:  interceptors,
+		streamWindow:  streamWindow,
 		cowboyClients: make(map[int]*CowboyClient),
 	}
 }
 
 // createCowboyclient establishes a connection to another cowboy and returns a client
-func createCowboyClient(podName string, serviceName string, grpcPort int, id int) (*CowboyClient, error) {
-	hostname := fmt.Sprintf("%s-%d.%s:%d", podName, id, serviceName, grpcPort)
-
+func createCowboyClient(resolver resolver.Resolver, creds *tlsconfig.Credentials, signer *shotauth.Signer, interceptors []grpc.UnaryClientInterceptor, id int) (*CowboyClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// dial cowboy
-	conn, err := grpc.DialContext(ctx, hostname, grpc.WithTransportCredentials(insecure.NewCredentials())) // TODO insecure
+	// find cowboy
+	address, err := resolver.Resolve(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// create client
-	client := damagepb.NewDamageServiceClient(conn)
+	// dial cowboy, every attempt of our shots is signed
+	interceptors = withSigning(interceptors, signer, id)
+	conn, err := grpc.DialContext(ctx, address,
+		creds.DialOption(tlsconfig.CowboySPIFFEID(id)),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+	)
+	if err != nil {
+		return nil, err
+	}
 
+	// create clients
 	return &CowboyClient{
-		client: client,
+		client:       damagepb.NewDamageServiceClient(conn),
+		clientV2:     damagev2pb.NewDamageServiceClient(conn),
+		interceptors: interceptors,
 	}, nil
 }
 
@@ -65,7 +92,7 @@ func (gsd *GRPCShotDispatcher) getClient(id int) (*CowboyClient, error) {
 	}
 
 	// create new cowboy client and store it in map
-	newClient, err := createCowboyClient(gsd.podName, gsd.serviceName, gsd.grpcPort, id)
+	newClient, err := createCowboyClient(gsd.resolver, gsd.creds, gsd.signer, gsd.interceptors, id)
 	if err != nil {
 		return nil, err
 	}
@@ -76,20 +103,91 @@ func (gsd *GRPCShotDispatcher) getClient(id int) (*CowboyClient, error) {
 }
 
 // Shoot sends the shot to another cowboy
-func (gsd *GRPCShotDispatcher) Shoot(ctx context.Context, id int, from int64, damage int64) error {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
-
+func (gsd *GRPCShotDispatcher) Shoot(ctx context.Context, id int, from int64, damage int64) (*damagev2pb.DamageResponse, error) {
 	c, err := gsd.getClient(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if !c.legacy {
+		resp, err := gsd.shootV2(ctx, id, c, &damagev2pb.DamageRequest{
+			From:   from,
+			Damage: damage,
+		})
+		if status.Code(err) != codes.Unimplemented {
+			if err != nil {
+				return nil, fmt.Errorf("failed to send damage: %w", err)
+			}
+
+			return resp, nil
+		}
+
+		gsd.logger.Info("peer doesn't serve the v2 damage API, falling back to v1", zap.Int("peer", id))
+
+		if c.stream != nil {
+			c.stream.close()
+			c.stream = nil
+		}
+
+		c.legacy = true
 	}
 
-	// apply damage
-	_, err = c.client.ReceiveDamage(ctx, &damagepb.DamageRequest{From: from, Damage: damage})
+	// apply damage, v1 doesn't tell the outcome
+	_, err = c.client.ReceiveDamage(ctx, &damagepb.DamageRequest{
+		From:   from,
+		Damage: damage,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to send damage: %w", err)
+		return nil, fmt.Errorf("failed to send damage: %w", err)
 	}
 
-	return nil
+	return &damagev2pb.DamageResponse{}, nil
+}
+
+// shootV2 sends the shot with the v2 API, preferring the stream
+func (gsd *GRPCShotDispatcher) shootV2(ctx context.Context, id int, c *CowboyClient, req *damagev2pb.DamageRequest) (*damagev2pb.DamageResponse, error) {
+	stream := gsd.getStream(id, c)
+	if stream == nil {
+		return c.clientV2.ReceiveDamage(ctx, req)
+	}
+
+	// shots on the stream go through the same interceptors as unary calls
+	var resp *damagev2pb.DamageResponse
+
+	err := interceptors.Invoke(ctx, c.interceptors, damagev2pb.DamageService_ReceiveDamage_FullMethodName, req, nil,
+		func(ctx context.Context, _ string, req, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			var err error
+			resp, err = stream.shoot(ctx, req.(*damagev2pb.DamageRequest))
+			return err
+		},
+	)
+
+	return resp, err
+}
+
+// getStream returns the damage stream to the peer, reopening it if it broke,
+// or nil if streams are disabled
+func (gsd *GRPCShotDispatcher) getStream(id int, c *CowboyClient) *damageStream {
+	if gsd.streamWindow <= 0 {
+		return nil
+	}
+
+	if c.stream != nil && !c.stream.broken() {
+		return c.stream
+	}
+
+	if c.stream != nil {
+		c.stream.close()
+		c.stream = nil
+	}
+
+	stream, err := openDamageStream(c.clientV2, gsd.streamWindow)
+	if err != nil {
+		gsd.logger.Debug("open damage stream", zap.Int("peer", id), zap.Error(err))
+		return nil
+	}
+
+	c.stream = stream
+
+	return stream
 }
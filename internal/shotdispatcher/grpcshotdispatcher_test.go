@@ -0,0 +1,159 @@
+package shotdispatcher
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/handlers/damagehandler"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/resolver"
+	"wildwest/internal/shotauth"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
+
+func TestGRPCShotDispatcher(t *testing.T) {
+	tests := []struct {
+		name          string
+		legacyPeer    bool
+		streamWindow  int
+		targetHealth  int
+		shooterHealth int
+		wantHealth    string
+		wantOutcome   damagev2pb.Outcome
+		wantErr       bool
+	}{
+		{"stream", false, 4, 10, 5, "7", damagev2pb.Outcome_OUTCOME_HIT, false},
+		{"stream from dead shooter", false, 4, 10, 0, "10", damagev2pb.Outcome_OUTCOME_UNSPECIFIED, true},
+		{"stream killing shot", false, 4, 3, 5, "0", damagev2pb.Outcome_OUTCOME_KILLED, false},
+		{"stream at dead target", false, 4, 0, 5, "0", damagev2pb.Outcome_OUTCOME_TARGET_DEAD, false},
+		{"unary", false, 0, 10, 5, "7", damagev2pb.Outcome_OUTCOME_HIT, false},
+		{"fallback to v1", true, 4, 10, 5, "7", damagev2pb.Outcome_OUTCOME_UNSPECIFIED, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", strconv.Itoa(tc.targetHealth)))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", strconv.Itoa(tc.shooterHealth)))
+
+			damageApplier := damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {})
+
+			grpcServer := grpc.NewServer()
+			damagepb.RegisterDamageServiceServer(grpcServer, damagehandler.NewGRPC(zap.NewNop(), damageApplier, nil, nil))
+			if !tc.legacyPeer {
+				damagev2pb.RegisterDamageServiceServer(grpcServer, damagehandler.NewGRPCV2(zap.NewNop(), damageApplier, nil, nil))
+			}
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+
+			go grpcServer.Serve(lis) //nolint:errcheck
+			defer grpcServer.Stop()
+
+			gsd := NewGRPC(zap.NewNop(), resolver.NewStatic([]string{"", lis.Addr().String()}), nil, nil, nil, tc.streamWindow)
+
+			// execute
+			resp, err := gsd.Shoot(ctx, 1, 2, 3)
+
+			// verify
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.wantOutcome, resp.GetOutcome())
+			assert.Equal(t, tc.legacyPeer, gsd.cowboyClients[1].legacy)
+
+			got, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHealth, got)
+		})
+	}
+}
+
+// flakyDamageApplier fails the first shot after it was verified, like an unavailable datastore
+type flakyDamageApplier struct {
+	damageapplier.DamageApplier
+	attempts int
+}
+
+func (fda *flakyDamageApplier) ApplyDamage(ctx context.Context, from, damage int) (int, error) {
+	fda.attempts++
+	if fda.attempts == 1 {
+		return 0, status.Error(codes.Unavailable, "datastore unavailable")
+	}
+
+	return fda.DamageApplier.ApplyDamage(ctx, from, damage)
+}
+
+func TestGRPCShotDispatcherRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		streamWindow int
+	}{
+		{"stream", 4},
+		{"unary", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"1", "10"))
+			assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"2", "5"))
+
+			publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+			assert.NoError(t, err)
+
+			verifier := shotauth.NewVerifier(1, []ed25519.PublicKey{nil, nil, publicKey}, time.Minute)
+			damageApplier := &flakyDamageApplier{DamageApplier: damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {})}
+
+			grpcServer := grpc.NewServer()
+			damagev2pb.RegisterDamageServiceServer(grpcServer, damagehandler.NewGRPCV2(zap.NewNop(), damageApplier, verifier, nil))
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+
+			go grpcServer.Serve(lis) //nolint:errcheck
+			defer grpcServer.Stop()
+
+			retry := []grpc.UnaryClientInterceptor{interceptors.ClientRetry(map[codes.Code]int{codes.Unavailable: 1}, time.Millisecond)}
+			gsd := NewGRPC(zap.NewNop(), resolver.NewStatic([]string{"", lis.Addr().String()}), nil, shotauth.NewSigner(privateKey), retry, tc.streamWindow)
+
+			// execute
+			resp, err := gsd.Shoot(ctx, 1, 2, 3)
+
+			// verify the retry isn't rejected as replayed
+			assert.NoError(t, err)
+			assert.Equal(t, damagev2pb.Outcome_OUTCOME_HIT, resp.GetOutcome())
+			assert.Equal(t, 2, damageApplier.attempts)
+
+			got, err := fakeDatastore.Get(ctx, utils.CowboyKeyPrefix+"1")
+			assert.NoError(t, err)
+			assert.Equal(t, "7", got)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package shotdispatcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"wildwest/internal/httpgateway"
+	"wildwest/internal/interceptors"
+	"wildwest/internal/resolver"
+	"wildwest/internal/shotauth"
+	"wildwest/internal/tlsconfig"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
+
+type HTTPShotDispatcher struct {
+	logger       *zap.Logger
+	resolver     resolver.Resolver
+	creds        *tlsconfig.Credentials
+	signer       *shotauth.Signer
+	interceptors []grpc.UnaryClientInterceptor
+	clients      map[int]*http.Client
+	// legacy holds the cowboys which only serve the v1 API
+	legacy map[int]bool
+}
+
+var _ ShotDispatcher = (*HTTPShotDispatcher)(nil)
+
+func NewHTTP(logger *zap.Logger, resolver resolver.Resolver, creds *tlsconfig.Credentials, signer *shotauth.Signer, interceptors []grpc.UnaryClientInterceptor) *HTTPShotDispatcher {
+	return &HTTPShotDispatcher{
+		logger:       logger,
+		resolver:     resolver,
+		creds:        creds,
+		signer:       signer,
+		interceptors: interceptors,
+		clients:      make(map[int]*http.Client),
+		legacy:       make(map[int]bool),
+	}
+}
+
+// getClient returns the http client for a cowboy lazily, with mTLS every cowboy needs its own expected identity
+func (hsd *HTTPShotDispatcher) getClient(id int) *http.Client {
+	if !hsd.creds.Enabled() {
+		return http.DefaultClient
+	}
+
+	if client, ok := hsd.clients[id]; ok {
+		return client
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   hsd.creds.ClientConfig(tlsconfig.CowboySPIFFEID(id)),
+			ForceAttemptHTTP2: true,
+		},
+	}
+
+	hsd.clients[id] = client
+
+	return client
+}
+
+// Shoot sends the shot to another cowboy
+func (hsd *HTTPShotDispatcher) Shoot(ctx context.Context, id int, from int64, damage int64) (*damagev2pb.DamageResponse, error) {
+	scheme := "http"
+	if hsd.creds.Enabled() {
+		scheme = "https"
+	}
+
+	address, err := hsd.resolver.Resolve(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := fmt.Sprintf("%s://%s", scheme, address)
+
+	client := hsd.getClient(id)
+
+	chain := withSigning(hsd.interceptors, hsd.signer, id)
+
+	if !hsd.legacy[id] {
+		var resp *damagev2pb.DamageResponse
+
+		// apply damage through the same interceptor chain as gRPC calls
+		err := interceptors.Invoke(ctx, chain, damagev2pb.DamageService_ReceiveDamage_FullMethodName, &damagev2pb.DamageRequest{
+			From:   from,
+			Damage: damage,
+		}, nil,
+			func(ctx context.Context, _ string, req, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				var err error
+				resp, err = httpgateway.ReceiveDamageV2(ctx, client, baseURL, req.(*damagev2pb.DamageRequest))
+				return err
+			},
+		)
+		if status.Code(err) != codes.Unimplemented {
+			if err != nil {
+				return nil, fmt.Errorf("failed to send damage: %w", err)
+			}
+
+			return resp, nil
+		}
+
+		hsd.logger.Info("peer doesn't serve the v2 damage API, falling back to v1", zap.Int("peer", id))
+
+		hsd.legacy[id] = true
+	}
+
+	// apply damage, v1 doesn't tell the outcome
+	err = interceptors.Invoke(ctx, chain, damagepb.DamageService_ReceiveDamage_FullMethodName, &damagepb.DamageRequest{
+		From:   from,
+		Damage: damage,
+	}, nil,
+		func(ctx context.Context, _ string, req, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			return httpgateway.ReceiveDamage(ctx, client, baseURL, req.(*damagepb.DamageRequest))
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send damage: %w", err)
+	}
+
+	return &damagev2pb.DamageResponse{}, nil
+}
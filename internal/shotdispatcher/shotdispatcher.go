@@ -1,7 +1,12 @@
 package shotdispatcher
 
-import "context"
+import (
+	"context"
+
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
 
 type ShotDispatcher interface {
-	Shoot(ctx context.Context, id int, from int64, damage int64) error
+	// Shoot sends the shot to another cowboy, the outcome is unspecified if the cowboy only serves the v1 API
+	Shoot(ctx context.Context, id int, from int64, damage int64) (*damagev2pb.DamageResponse, error)
 }
@@ -3,8 +3,11 @@ package shotdispatcher
 import (
 	"context"
 	"wildwest/internal/damageapplier"
+	"wildwest/internal/handlers/damagehandler"
 
 	"go.uber.org/zap"
+
+	damagev2pb "wildwest/api/proto/damage/v2"
 )
 
 type FakeShotDispatcher struct {
@@ -22,7 +25,6 @@ func NewFake(logger *zap.Logger, damageAppliers []damageapplier.DamageApplier) *
 }
 
 // Shoot sends the shot to another cowboy
-func (fsd *FakeShotDispatcher) Shoot(ctx context.Context, id int, from int64, damage int64) error {
-	_, err := fsd.damageAppliers[id].ApplyDamage(ctx, int(from), int(damage))
-	return err
+func (fsd *FakeShotDispatcher) Shoot(ctx context.Context, id int, from int64, damage int64) (*damagev2pb.DamageResponse, error) {
+	return damagehandler.ApplyDamage(ctx, fsd.damageAppliers[id], int(from), int(damage))
 }
@@ -0,0 +1,52 @@
+package shotdispatcher
+
+import (
+	"context"
+	"fmt"
+	"wildwest/internal/shotauth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	damagepb "wildwest/api/proto/damage"
+	damagev2pb "wildwest/api/proto/damage/v2"
+)
+
+// withSigning returns the interceptor chain followed by one signing the shots at cowboy id. The signing interceptor
+// runs after the retry interceptor, so that every attempt has its own nonce and a retried shot isn't rejected as
+// replayed
+func withSigning(chain []grpc.UnaryClientInterceptor, signer *shotauth.Signer, id int) []grpc.UnaryClientInterceptor {
+	if signer == nil {
+		return chain
+	}
+
+	return append(chain[:len(chain):len(chain)], signShot(signer, id))
+}
+
+// signShot signs a copy of the shot at cowboy id for every attempt
+func signShot(signer *shotauth.Signer, id int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		switch shot := req.(type) {
+		case *damagev2pb.DamageRequest:
+			sig, err := signer.Sign(id, shot.GetFrom(), shot.GetDamage())
+			if err != nil {
+				return fmt.Errorf("sign shot: %w", err)
+			}
+
+			signed := proto.Clone(shot).(*damagev2pb.DamageRequest)
+			signed.Nonce, signed.SentAt, signed.Signature = sig.Nonce, sig.SentAt, sig.Signature
+			req = signed
+		case *damagepb.DamageRequest:
+			sig, err := signer.Sign(id, shot.GetFrom(), shot.GetDamage())
+			if err != nil {
+				return fmt.Errorf("sign shot: %w", err)
+			}
+
+			signed := proto.Clone(shot).(*damagepb.DamageRequest)
+			signed.Nonce, signed.SentAt, signed.Signature = sig.Nonce, sig.SentAt, sig.Signature
+			req = signed
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -5,14 +5,22 @@ import (
 	"errors"
 	"fmt"
 	"go.uber.org/zap"
-	"strings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
 	"wildwest/internal/shotdispatcher"
 	"wildwest/internal/shotqueue"
 	"wildwest/internal/targetprovider"
 	"wildwest/internal/utils"
+
+	damagev2pb "wildwest/api/proto/damage/v2"
 )
 
+// notRunningBackoff is how long we hold fire after a shot was rejected because the game isn't running for the target
+const notRunningBackoff = 250 * time.Millisecond
+
 type DefaultShotLooper struct {
 	logger         *zap.Logger
 	id             int
@@ -21,11 +29,14 @@ type DefaultShotLooper struct {
 	shotQueue      shotqueue.ShotQueue
 	shotSender     shotdispatcher.ShotDispatcher
 	targetProvider targetprovider.TargetProvider
+	gamePhase      *gamephase.Watcher
 }
 
 var _ ShotLooper = (*DefaultShotLooper)(nil)
 
-func New(logger *zap.Logger, id int, cowboy utils.Cowboy, db datastore.Datastore, shotQueue shotqueue.ShotQueue, shotSender shotdispatcher.ShotDispatcher, targetProvider targetprovider.TargetProvider) *DefaultShotLooper {
+// New creates a shot looper which doesn't shoot while the game is paused or over, a nil gamePhase lets it shoot at all
+// times
+func New(logger *zap.Logger, id int, cowboy utils.Cowboy, db datastore.Datastore, shotQueue shotqueue.ShotQueue, shotSender shotdispatcher.ShotDispatcher, targetProvider targetprovider.TargetProvider, gamePhase *gamephase.Watcher) *DefaultShotLooper {
 	return &DefaultShotLooper{
 		logger:         logger,
 		id:             id,
@@ -34,31 +45,88 @@ func New(logger *zap.Logger, id int, cowboy utils.Cowboy, db datastore.Datastore
 		shotQueue:      shotQueue,
 		shotSender:     shotSender,
 		targetProvider: targetProvider,
+		gamePhase:      gamePhase,
 	}
 }
 
-// StartShootingLoop begins shooting, exits once cowboy is either dead or the winner and returns true if cowboy won
+// StartShootingLoop begins shooting, exits once cowboy is either dead or the winner, or the game is over, and returns
+// true if cowboy won. It's started at the agreed start time, which decides when shooting begins, the game phase only
+// stops shooting while the game is paused and once it's over
 func (dsl *DefaultShotLooper) StartShootingLoop(ctx context.Context) bool {
 	for {
+		phase := dsl.gamePhase.Phase()
+
+		// wait until the game is resumed, dropping the shot queued meanwhile so that we don't fire it right away
+		if phase == gamephase.Paused {
+			var err error
+
+			phase, err = dsl.gamePhase.Wait(ctx, gamephase.Running, gamephase.Finished, gamephase.Aborted)
+			if err != nil {
+				return false
+			}
+
+			select {
+			case <-dsl.shotQueue.DequeueShot():
+			default:
+			}
+		}
+
+		if phase.Terminal() {
+			dsl.logger.Info("game is over", zap.String("game_phase", string(phase)))
+			return false
+		}
+
 		select {
 		case <-ctx.Done():
 			return false
 		case <-dsl.shotQueue.DequeueShot():
+			// the game may have been paused or be over since
+			if phase := dsl.gamePhase.Phase(); phase == gamephase.Paused || phase.Terminal() {
+				continue
+			}
+
 			if err := dsl.shootAtRandomCowboy(ctx); err != nil {
 				if errors.Is(err, targetprovider.ErrIAmTheWinner) {
 					return true
 				}
 
+				if errors.Is(err, targetprovider.ErrIAmDead) {
+					dsl.logger.Info("killed without being shot")
+					return false
+				}
+
 				if errors.Is(err, context.Canceled) {
 					return false
 				}
 
+				// the target's view of the game phase may be behind ours or the game was paused or ended since
+				if statusCode(err) == codes.FailedPrecondition {
+					dsl.logger.Debug("shot rejected, the game isn't running for the target", zap.Error(err))
+					dsl.holdFire(ctx)
+					go dsl.shotQueue.QueueShot()
+
+					continue
+				}
+
 				dsl.logger.Error("error shooting cowboy", zap.Error(err))
 			}
 		}
 	}
 }
 
+// holdFire waits until the game is paused or over in our view of the game phase, or notRunningBackoff passes
+func (dsl *DefaultShotLooper) holdFire(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, notRunningBackoff)
+	defer cancel()
+
+	if dsl.gamePhase == nil {
+		<-ctx.Done()
+		return
+	}
+
+	_, _ = dsl.gamePhase.Wait(ctx, gamephase.Paused, gamephase.Finished, gamephase.Aborted)
+}
+
 // shootAtRandomCowboy finds a random alive cowboy and attempts to shoot him
 func (dsl *DefaultShotLooper) shootAtRandomCowboy(ctx context.Context) error {
 	randomCowboyID, err := dsl.targetProvider.GetRandomTarget(ctx)
@@ -67,10 +135,10 @@ func (dsl *DefaultShotLooper) shootAtRandomCowboy(ctx context.Context) error {
 	}
 
 	// shoot the cowboy
-	if err := dsl.shotSender.Shoot(ctx, randomCowboyID, int64(dsl.id), dsl.cowboy.Damage); err != nil {
-		// immediately retry if transaction was unsuccessful
-		// TODO properly compare grpc errors
-		if strings.HasSuffix(err.Error(), datastore.ErrTransactionUnsuccessful.Error()) {
+	resp, err := dsl.shotSender.Shoot(ctx, randomCowboyID, int64(dsl.id), dsl.cowboy.Damage)
+	if err != nil {
+		// immediately retry if the damage transaction was unsuccessful
+		if statusCode(err) == codes.Aborted {
 			go dsl.shotQueue.QueueShot()
 			return nil
 		}
@@ -78,5 +146,25 @@ func (dsl *DefaultShotLooper) shootAtRandomCowboy(ctx context.Context) error {
 		return fmt.Errorf("send shot: %w", err)
 	}
 
+	// keep our view of the target up to date
+	if resp.GetOutcome() != damagev2pb.Outcome_OUTCOME_UNSPECIFIED {
+		dsl.targetProvider.UpdateTarget(randomCowboyID, int(resp.GetRemainingHealth()))
+	}
+
+	// the target was already dead, the shot wasn't used
+	if resp.GetOutcome() == damagev2pb.Outcome_OUTCOME_TARGET_DEAD {
+		go dsl.shotQueue.QueueShot()
+	}
+
 	return nil
 }
+
+// statusCode returns the gRPC status code of err, which may be wrapped
+func statusCode(err error) codes.Code {
+	var st interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &st) {
+		return st.GRPCStatus().Code()
+	}
+
+	return status.Code(err)
+}
@@ -1,9 +1,14 @@
 package shotqueue
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type TimedShotQueue struct {
 	shotQueue chan struct{}
+	done      chan struct{}
+	stopOnce  *sync.Once
 }
 
 var _ ShotQueue = (*TimedShotQueue)(nil)
@@ -11,6 +16,8 @@ var _ ShotQueue = (*TimedShotQueue)(nil)
 func New(frequency time.Duration) *TimedShotQueue {
 	tsq := &TimedShotQueue{
 		shotQueue: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		stopOnce:  &sync.Once{},
 	}
 
 	go tsq.queueTimedShots(frequency)
@@ -18,19 +25,40 @@ func New(frequency time.Duration) *TimedShotQueue {
 	return tsq
 }
 
+// queueTimedShots queues a shot every frequency, ticks are dropped while a shot is queued so that shots don't pile up
+// while nobody dequeues them, e.g. while the game is paused
 func (tsq *TimedShotQueue) queueTimedShots(frequency time.Duration) {
 	ticker := time.NewTicker(frequency)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		tsq.QueueShot()
+	for {
+		select {
+		case <-tsq.done:
+			return
+		case <-ticker.C:
+			select {
+			case tsq.shotQueue <- struct{}{}:
+			default:
+			}
+		}
 	}
 }
 
+// QueueShot queues a shot, blocking until the previous one is dequeued or the queue is stopped
 func (tsq *TimedShotQueue) QueueShot() {
-	tsq.shotQueue <- struct{}{}
+	select {
+	case tsq.shotQueue <- struct{}{}:
+	case <-tsq.done:
+	}
 }
 
 func (tsq *TimedShotQueue) DequeueShot() <-chan struct{} {
 	return tsq.shotQueue
 }
+
+// Stop stops queueing timed shots and releases blocked QueueShot calls
+func (tsq *TimedShotQueue) Stop() {
+	tsq.stopOnce.Do(func() {
+		close(tsq.done)
+	})
+}
@@ -6,69 +6,151 @@ import (
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"wildwest/internal/datastore"
 	"wildwest/internal/utils"
 )
 
 type DefaultTargetProvider struct {
-	id int
-	db datastore.Datastore
+	id              int
+	db              datastore.Datastore
+	refreshInterval time.Duration
+
+	mu          *sync.Mutex
+	healths     map[int]int
+	refreshedAt time.Time
 }
 
 var _ TargetProvider = (*DefaultTargetProvider)(nil)
 
-func New(id int, db datastore.Datastore) *DefaultTargetProvider {
+// New creates a target provider which keeps a local view of the cowboys' health,
+// the view is refreshed from the datastore once it's older than refreshInterval
+func New(id int, db datastore.Datastore, refreshInterval time.Duration) *DefaultTargetProvider {
 	return &DefaultTargetProvider{
-		id: id,
-		db: db,
+		id:              id,
+		db:              db,
+		refreshInterval: refreshInterval,
+		mu:              &sync.Mutex{},
+		healths:         make(map[int]int),
 	}
 }
 
 // GetRandomTarget returns a random alive cowboy's id
 func (dtp *DefaultTargetProvider) GetRandomTarget(ctx context.Context) (int, error) {
-	ourKey := utils.CowboyKeyPrefix + strconv.Itoa(dtp.id)
+	dtp.mu.Lock()
+	defer dtp.mu.Unlock()
 
-	// get alive cowboy keys
-	// TODO this will return unwanted keys if there are other keys with the prefix
-	resp, err := dtp.db.GetPrefix(ctx, utils.CowboyKeyPrefix)
-	if err != nil {
-		return 0, fmt.Errorf("get alive cowboys: %w", err)
+	refreshed := false
+
+	if time.Since(dtp.refreshedAt) >= dtp.refreshInterval {
+		if err := dtp.refreshNoLock(ctx); err != nil {
+			return 0, err
+		}
+
+		refreshed = true
 	}
 
-	// filter out dead cowboys
-	aliveCowboyKeys := make([]string, 0, len(resp))
+	aliveCowboys := dtp.aliveCowboysNoLock()
 
-	for k, v := range resp {
-		if v > "0" {
-			aliveCowboyKeys = append(aliveCowboyKeys, k)
+	// our view might be out of date, only trust the datastore to declare us dead or the winner
+	if !refreshed && (dtp.deadNoLock() || len(aliveCowboys) == 0 || len(aliveCowboys) == 1 && aliveCowboys[0] == dtp.id) {
+		if err := dtp.refreshNoLock(ctx); err != nil {
+			return 0, err
 		}
+
+		aliveCowboys = dtp.aliveCowboysNoLock()
+	}
+
+	// we were killed without being shot, e.g. by the game master
+	if dtp.deadNoLock() {
+		return 0, ErrIAmDead
 	}
 
 	// if i am the only one left
-	if len(aliveCowboyKeys) == 1 {
-		if aliveCowboyKeys[0] == ourKey {
+	if len(aliveCowboys) == 1 {
+		if aliveCowboys[0] == dtp.id {
 			return 0, ErrIAmTheWinner
 		}
 	}
 
 	// if response is empty
-	if len(aliveCowboyKeys) == 0 {
+	if len(aliveCowboys) == 0 {
 		return 0, ErrInvalidDatastoreState
 	}
 
 	// generate random idx
-	randomIdx := rand.Intn(len(aliveCowboyKeys))
-	for aliveCowboyKeys[randomIdx] == ourKey {
-		randomIdx = rand.Intn(len(aliveCowboyKeys))
+	randomIdx := rand.Intn(len(aliveCowboys))
+	for aliveCowboys[randomIdx] == dtp.id {
+		randomIdx = rand.Intn(len(aliveCowboys))
 	}
 
-	randomCowboyKey := aliveCowboyKeys[randomIdx]
-	randomCowboyIDStr := strings.TrimPrefix(randomCowboyKey, utils.CowboyKeyPrefix)
+	return aliveCowboys[randomIdx], nil
+}
+
+// UpdateTarget updates our view of a cowboy's health with the outcome of a shot
+func (dtp *DefaultTargetProvider) UpdateTarget(id int, health int) {
+	dtp.mu.Lock()
+	defer dtp.mu.Unlock()
+
+	dtp.healths[id] = health
+}
 
-	randomCowboyID, err := strconv.Atoi(randomCowboyIDStr)
+// Invalidate makes us refresh our view before picking the next target, e.g. once the game master changed a cowboy's
+// health
+func (dtp *DefaultTargetProvider) Invalidate() {
+	dtp.mu.Lock()
+	defer dtp.mu.Unlock()
+
+	dtp.refreshedAt = time.Time{}
+}
+
+// refreshNoLock replaces our view with the cowboys' health in the datastore
+func (dtp *DefaultTargetProvider) refreshNoLock(ctx context.Context) error {
+	// get cowboy keys
+	resp, err := dtp.db.GetPrefix(ctx, utils.CowboyKeyPrefix)
 	if err != nil {
-		return 0, fmt.Errorf("convert random cowboy id to int: %w", err)
+		return fmt.Errorf("get alive cowboys: %w", err)
+	}
+
+	healths := make(map[int]int, len(resp))
+
+	for k, v := range resp {
+		id, err := strconv.Atoi(strings.TrimPrefix(k, utils.CowboyKeyPrefix))
+		if err != nil {
+			return fmt.Errorf("convert cowboy id to int: %w", err)
+		}
+
+		health, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("convert cowboy health to int: %w", err)
+		}
+
+		healths[id] = health
+	}
+
+	dtp.healths = healths
+	dtp.refreshedAt = time.Now()
+
+	return nil
+}
+
+// deadNoLock reports whether we're dead in our view, we're not in it before our health is initialized
+func (dtp *DefaultTargetProvider) deadNoLock() bool {
+	health, ok := dtp.healths[dtp.id]
+
+	return ok && health <= 0
+}
+
+// aliveCowboysNoLock returns the ids of the cowboys alive in our view
+func (dtp *DefaultTargetProvider) aliveCowboysNoLock() []int {
+	aliveCowboys := make([]int, 0, len(dtp.healths))
+
+	for id, health := range dtp.healths {
+		if health > 0 {
+			aliveCowboys = append(aliveCowboys, id)
+		}
 	}
 
-	return randomCowboyID, nil
+	return aliveCowboys
 }
@@ -7,9 +7,11 @@ import (
 
 const (
 	ErrIAmTheWinner          = utils.ConstError("i am the winner")
+	ErrIAmDead               = utils.ConstError("i am dead")
 	ErrInvalidDatastoreState = utils.ConstError("invalid datastore state")
 )
 
 type TargetProvider interface {
 	GetRandomTarget(ctx context.Context) (int, error)
+	UpdateTarget(id int, health int)
 }
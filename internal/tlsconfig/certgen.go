@@ -0,0 +1,210 @@
+package tlsconfig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	CAFileName      = "ca.pem"
+	caKeyFileName   = "ca-key.pem"
+	adminCertName   = "admin"
+	certificateTTL  = 365 * 24 * time.Hour
+	serialNumberLen = 128
+)
+
+// CA is a certificate authority which can issue SPIFFE certificates
+type CA struct {
+	certificate *x509.Certificate
+	key         crypto.Signer
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+// GenerateCA creates a new self-signed certificate authority
+func GenerateCA() (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate CA key: %w", err)
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: TrustDomain + " CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(certificateTTL),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("create CA certificate: %w", err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse CA certificate: %w", err)
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		certificate: certificate,
+		key:         key,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      keyPEM,
+	}, nil
+}
+
+// CertPEM returns the PEM encoded CA certificate
+func (ca *CA) CertPEM() []byte {
+	return ca.certPEM
+}
+
+// Issue creates a certificate for the given SPIFFE ID, usable both as a client and as a server certificate
+func (ca *CA) Issue(spiffeID string, dnsNames []string) (certPEM []byte, keyPEM []byte, err error) {
+	uri, err := url.Parse(spiffeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse SPIFFE ID: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: spiffeID},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(certificateTTL),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{uri},
+		DNSNames:     dnsNames,
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, key.Public(), ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create certificate: %w", err)
+	}
+
+	keyPEM, err = encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// GenerateAll writes a new CA and certificates for every cowboy, the controller, etcd and operators into dir.
+// Cowboy certificates are named after the StatefulSet hostname, e.g. cowboy-0.pem and cowboy-0-key.pem, the operator
+// certificate is admin.pem
+func GenerateAll(dir string, replicas int, cowboyAppName string, controllerAppName string, etcdAppName string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	ca, err := GenerateCA()
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(dir, CAFileName, ca.certPEM); err != nil {
+		return err
+	}
+
+	if err := writeFile(dir, caKeyFileName, ca.keyPEM); err != nil {
+		return err
+	}
+
+	for id := 0; id < replicas; id++ {
+		hostname := fmt.Sprintf("%s-%d", cowboyAppName, id)
+
+		if err := issueToFiles(ca, dir, hostname, CowboySPIFFEID(id), []string{
+			hostname,
+			fmt.Sprintf("%s.%s", hostname, cowboyAppName),
+			"localhost",
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := issueToFiles(ca, dir, controllerAppName, ControllerSPIFFEID, []string{controllerAppName, "localhost"}); err != nil {
+		return err
+	}
+
+	if err := issueToFiles(ca, dir, etcdAppName, EtcdSPIFFEID, []string{etcdAppName, "localhost"}); err != nil {
+		return err
+	}
+
+	return issueToFiles(ca, dir, adminCertName, AdminSPIFFEID, []string{"localhost"})
+}
+
+// issueToFiles issues a certificate and writes it to <name>.pem and <name>-key.pem
+func issueToFiles(ca *CA, dir string, name string, spiffeID string, dnsNames []string) error {
+	certPEM, keyPEM, err := ca.Issue(spiffeID, dnsNames)
+	if err != nil {
+		return fmt.Errorf("issue certificate for %s: %w", name, err)
+	}
+
+	if err := writeFile(dir, name+".pem", certPEM); err != nil {
+		return err
+	}
+
+	return writeFile(dir, name+"-key.pem", keyPEM)
+}
+
+func writeFile(dir string, name string, data []byte) error {
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberLen))
+	if err != nil {
+		return nil, fmt.Errorf("generate serial number: %w", err)
+	}
+
+	return serialNumber, nil
+}
@@ -0,0 +1,119 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	spiffeScheme       = "spiffe"
+	TrustDomain        = "wildwest"
+	cowboyPathPrefix   = "/cowboy/"
+	ControllerSPIFFEID = "spiffe://" + TrustDomain + "/controller"
+	EtcdSPIFFEID       = "spiffe://" + TrustDomain + "/etcd"
+	// AdminSPIFFEID identifies operators calling the controller's admin service
+	AdminSPIFFEID = "spiffe://" + TrustDomain + "/admin"
+)
+
+// CowboySPIFFEID returns the SPIFFE ID of the cowboy with the given id
+func CowboySPIFFEID(id int) string {
+	return "spiffe://" + TrustDomain + cowboyPathPrefix + strconv.Itoa(id)
+}
+
+// CowboyIDFromSPIFFEID returns the cowboy id from a cowboy SPIFFE ID
+func CowboyIDFromSPIFFEID(spiffeID string) (int, error) {
+	path, ok := strings.CutPrefix(spiffeID, "spiffe://"+TrustDomain+cowboyPathPrefix)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q is not a cowboy", ErrUnexpectedPeerID, spiffeID)
+	}
+
+	id, err := strconv.Atoi(path)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a cowboy", ErrUnexpectedPeerID, spiffeID)
+	}
+
+	return id, nil
+}
+
+// IDFromCertificate returns the SPIFFE ID in the certificate's URI SANs
+func IDFromCertificate(cert *x509.Certificate) (string, error) {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == spiffeScheme && uri.Host == TrustDomain {
+			return uri.String(), nil
+		}
+	}
+
+	return "", ErrNoPeerIdentity
+}
+
+// PeerID returns the verified SPIFFE ID of the caller, ok is false if the caller didn't authenticate with mTLS or its
+// certificate has no SPIFFE ID
+func PeerID(ctx context.Context) (id string, ok bool) {
+	id, err := verifiedPeerID(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	return id, true
+}
+
+// verifiedPeerID returns the verified SPIFFE ID of the caller, errNoTLS if the caller didn't connect over TLS and
+// ErrNoPeerIdentity if its certificate has no SPIFFE ID
+func verifiedPeerID(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", errNoTLS
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", errNoTLS
+	}
+
+	if len(tlsInfo.State.VerifiedChains) == 0 {
+		return "", ErrNoPeerIdentity
+	}
+
+	return IDFromCertificate(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// authorize checks that a TLS caller has the given SPIFFE ID, callers are only allowed without it when the server
+// runs without TLS
+func authorize(ctx context.Context, want string) error {
+	peerID, err := verifiedPeerID(ctx)
+	if errors.Is(err, errNoTLS) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if peerID != want {
+		return fmt.Errorf("%w: got %q, want %q", ErrUnexpectedPeerID, peerID, want)
+	}
+
+	return nil
+}
+
+// AuthorizeCowboy checks that a TLS caller is the cowboy with the given id, callers without TLS are allowed
+func AuthorizeCowboy(ctx context.Context, id int) error {
+	return authorize(ctx, CowboySPIFFEID(id))
+}
+
+// AuthorizeController checks that a TLS caller is the controller, callers without TLS are allowed
+func AuthorizeController(ctx context.Context) error {
+	return authorize(ctx, ControllerSPIFFEID)
+}
+
+// AuthorizeAdmin checks that a TLS caller is an operator, callers without TLS are allowed
+func AuthorizeAdmin(ctx context.Context) error {
+	return authorize(ctx, AdminSPIFFEID)
+}
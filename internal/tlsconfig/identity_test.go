@@ -0,0 +1,57 @@
+package tlsconfig_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"testing"
+	"wildwest/internal/tlsconfig"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// tlsPeerContext returns a context of a caller which presented the verified certificate
+func tlsPeerContext(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"without TLS", context.Background(), nil},
+		{"plaintext peer", peer.NewContext(context.Background(), &peer.Peer{}), nil},
+		{"admin", tlsPeerContext(&x509.Certificate{URIs: []*url.URL{mustParseURL(t, tlsconfig.AdminSPIFFEID)}}), nil},
+		{"cowboy", tlsPeerContext(&x509.Certificate{URIs: []*url.URL{mustParseURL(t, tlsconfig.CowboySPIFFEID(1))}}), tlsconfig.ErrUnexpectedPeerID},
+		{"no SPIFFE ID", tlsPeerContext(&x509.Certificate{}), tlsconfig.ErrNoPeerIdentity},
+		{"another trust domain", tlsPeerContext(&x509.Certificate{URIs: []*url.URL{mustParseURL(t, "spiffe://other/admin")}}), tlsconfig.ErrNoPeerIdentity},
+		{"no verified chains", peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}}), tlsconfig.ErrNoPeerIdentity},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// execute
+			err := tlsconfig.AuthorizeAdmin(tc.ctx)
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// mustParseURL parses a SPIFFE ID into a certificate URI SAN
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	assert.NoError(t, err)
+
+	return u
+}
@@ -0,0 +1,139 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"wildwest/internal/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	ErrNoCACertificates = utils.ConstError("no CA certificates found")
+	ErrNoPeerIdentity   = utils.ConstError("peer has no SPIFFE identity")
+	ErrUnexpectedPeerID = utils.ConstError("unexpected peer identity")
+
+	errNoTLS = utils.ConstError("peer didn't connect over TLS")
+)
+
+// Config holds the certificate, key and CA paths, paths are expanded with environment variables (e.g. ${HOSTNAME})
+type Config struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Credentials holds our certificate and the CA pool used to verify peers, nil Credentials mean mTLS is disabled
+type Credentials struct {
+	certificate tls.Certificate
+	caPool      *x509.CertPool
+}
+
+// Load reads the certificate, key and CA from the configured paths, returns nil Credentials if mTLS is disabled
+func Load(cfg Config) (*Credentials, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(os.ExpandEnv(cfg.CertFile), os.ExpandEnv(cfg.KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load key pair: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(os.ExpandEnv(cfg.CAFile))
+	if err != nil {
+		return nil, fmt.Errorf("read CA file: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, ErrNoCACertificates
+	}
+
+	return &Credentials{
+		certificate: certificate,
+		caPool:      caPool,
+	}, nil
+}
+
+// Enabled returns whether mTLS is enabled
+func (c *Credentials) Enabled() bool {
+	return c != nil
+}
+
+// ServerConfig returns a TLS config which requires and verifies client certificates
+func (c *Credentials) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		Certificates: []tls.Certificate{c.certificate},
+		ClientCAs:    c.caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+}
+
+// ClientConfig returns a TLS config which presents our certificate and only accepts a server with the expected SPIFFE ID.
+// Hostname verification is replaced by the SPIFFE ID check, so the chain is verified in VerifyConnection
+func (c *Credentials) ClientConfig(expectedID string) *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		Certificates:       []tls.Certificate{c.certificate},
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			id, err := verifyChain(cs.PeerCertificates, c.caPool, x509.ExtKeyUsageServerAuth)
+			if err != nil {
+				return err
+			}
+
+			if id != expectedID {
+				return fmt.Errorf("%w: got %q, want %q", ErrUnexpectedPeerID, id, expectedID)
+			}
+
+			return nil
+		},
+	}
+}
+
+// ServerOption returns the gRPC server credentials option
+func (c *Credentials) ServerOption() grpc.ServerOption {
+	if !c.Enabled() {
+		return grpc.Creds(insecure.NewCredentials())
+	}
+
+	return grpc.Creds(credentials.NewTLS(c.ServerConfig()))
+}
+
+// DialOption returns the gRPC dial credentials option for a server with the expected SPIFFE ID
+func (c *Credentials) DialOption(expectedID string) grpc.DialOption {
+	if !c.Enabled() {
+		return grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(c.ClientConfig(expectedID)))
+}
+
+// verifyChain verifies the peer certificate chain against the CA pool and returns the leaf's SPIFFE ID
+func verifyChain(certs []*x509.Certificate, caPool *x509.CertPool, usage x509.ExtKeyUsage) (string, error) {
+	if len(certs) == 0 {
+		return "", ErrNoPeerIdentity
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         caPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	}); err != nil {
+		return "", fmt.Errorf("verify peer certificate: %w", err)
+	}
+
+	return IDFromCertificate(certs[0])
+}
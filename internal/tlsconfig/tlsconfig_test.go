@@ -0,0 +1,129 @@
+package tlsconfig_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+	damagepb "wildwest/api/proto/damage"
+	"wildwest/internal/damageapplier"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/handlers/damagehandler"
+	"wildwest/internal/tlsconfig"
+	"wildwest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// loadCowboyCredentials loads the credentials of a cowboy generated by tlsconfig.GenerateAll
+func loadCowboyCredentials(t *testing.T, dir string, name string) *tlsconfig.Credentials {
+	t.Helper()
+
+	creds, err := tlsconfig.Load(tlsconfig.Config{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, name+".pem"),
+		KeyFile:  filepath.Join(dir, name+"-key.pem"),
+		CAFile:   filepath.Join(dir, tlsconfig.CAFileName),
+	})
+	assert.NoError(t, err)
+
+	return creds
+}
+
+// startDamageServer starts a mTLS damage server for cowboy 1 and returns its address
+func startDamageServer(t *testing.T, creds *tlsconfig.Credentials) string {
+	t.Helper()
+
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+
+	for _, key := range []string{"1", "2", "3"} {
+		assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+key, "10"))
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	grpcServer := grpc.NewServer(creds.ServerOption())
+	damagepb.RegisterDamageServiceServer(grpcServer, damagehandler.NewGRPC(zap.NewNop(), damageapplier.New(zap.NewNop(), 1, fakeDatastore, func() {}), nil, nil))
+
+	go grpcServer.Serve(lis) //nolint:errcheck
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, tlsconfig.GenerateAll(dir, 3, "cowboy", "cowboy-controller", "etcd"))
+
+	otherDir := t.TempDir()
+	assert.NoError(t, tlsconfig.GenerateAll(otherDir, 3, "cowboy", "cowboy-controller", "etcd"))
+
+	addr := startDamageServer(t, loadCowboyCredentials(t, dir, "cowboy-1"))
+
+	tests := []struct {
+		name       string
+		clientDir  string
+		clientName string
+		expectedID string
+		from       int64
+		want       codes.Code
+	}{
+		{"authenticated shooter", dir, "cowboy-2", tlsconfig.CowboySPIFFEID(1), 2, codes.OK},
+		{"spoofed shooter", dir, "cowboy-2", tlsconfig.CowboySPIFFEID(1), 3, codes.PermissionDenied},
+		{"unexpected server identity", dir, "cowboy-2", tlsconfig.CowboySPIFFEID(0), 2, codes.Unavailable},
+		{"client from another CA", otherDir, "cowboy-2", tlsconfig.CowboySPIFFEID(1), 2, codes.Unavailable},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			clientCreds := loadCowboyCredentials(t, tc.clientDir, tc.clientName)
+
+			conn, err := grpc.Dial(addr, clientCreds.DialOption(tc.expectedID))
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			_, err = damagepb.NewDamageServiceClient(conn).ReceiveDamage(ctx, &damagepb.DamageRequest{From: tc.from, Damage: 1})
+			assert.Equal(t, tc.want, status.Code(err), err)
+		})
+	}
+}
+
+func TestLoadDisabled(t *testing.T) {
+	creds, err := tlsconfig.Load(tlsconfig.Config{Enabled: false})
+	assert.NoError(t, err)
+	assert.False(t, creds.Enabled())
+}
+
+func TestCowboyIDFromSPIFFEID(t *testing.T) {
+	tests := []struct {
+		spiffeID string
+		want     int
+		err      error
+	}{
+		{tlsconfig.CowboySPIFFEID(0), 0, nil},
+		{tlsconfig.CowboySPIFFEID(42), 42, nil},
+		{tlsconfig.ControllerSPIFFEID, 0, tlsconfig.ErrUnexpectedPeerID},
+		{"spiffe://other/cowboy/1", 0, tlsconfig.ErrUnexpectedPeerID},
+		{"spiffe://wildwest/cowboy/foo", 0, tlsconfig.ErrUnexpectedPeerID},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spiffeID, func(t *testing.T) {
+			got, err := tlsconfig.CowboyIDFromSPIFFEID(tc.spiffeID)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
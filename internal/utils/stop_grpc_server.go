@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// StopGRPCServer stops the server gracefully, waiting for pending RPCs to finish, and forcefully once timeout passes.
+// Returns false if the server had to be stopped forcefully
+func StopGRPCServer(server *grpc.Server, timeout time.Duration) bool {
+	stopped := make(chan struct{})
+
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		return true
+	case <-timer.C:
+		server.Stop()
+		<-stopped
+
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestStopGRPCServer(t *testing.T) {
+	tests := []struct {
+		name         string
+		pendingWatch bool
+		wantGraceful bool
+	}{
+		{"idle", false, true},
+		{"pending stream", true, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			server := grpc.NewServer()
+			healthpb.RegisterHealthServer(server, health.NewServer())
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+
+			go server.Serve(lis) //nolint:errcheck
+
+			conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			// health watches stream until they're canceled
+			if tc.pendingWatch {
+				stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+				assert.NoError(t, err)
+
+				_, err = stream.Recv()
+				assert.NoError(t, err)
+			}
+
+			// execute
+			graceful := StopGRPCServer(server, 100*time.Millisecond)
+
+			// verify
+			assert.Equal(t, tc.wantGraceful, graceful)
+		})
+	}
+}
@@ -1,11 +1,47 @@
 package utils
 
 import (
+	"time"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-const CowboyKeyPrefix = "cowboy-"
+// CowboyKeyPrefix is the namespace of the cowboys' health keys, e.g. health/1. Health is read by prefix and the other
+// keys are outside of the namespace, which ends in a delimiter so that they can't start with it
+const CowboyKeyPrefix = "health/"
+
+const (
+	ShotTransportGRPC = "grpc"
+	ShotTransportHTTP = "http"
+)
+
+const (
+	PeerDiscoveryStatefulSet = "statefulset"
+	PeerDiscoveryStatic      = "static"
+	PeerDiscoveryDNS         = "dns"
+	PeerDiscoveryRegistry    = "registry"
+)
+
+const (
+	ReadinessTimeoutPolicyStart = "start"
+	ReadinessTimeoutPolicyAbort = "abort"
+)
+
+const (
+	StartModePush = "push"
+	StartModePull = "pull"
+)
+
+const (
+	ShutdownPolicyResume  = "resume"
+	ShutdownPolicyForfeit = "forfeit"
+)
+
+const (
+	GameOverPolicyStay = "stay"
+	GameOverPolicyExit = "exit"
+)
 
 type Cowboy struct {
 	Name   string `json:"name"`
@@ -22,6 +58,100 @@ type Environment struct {
 	GRPCPort           int    `env:"GRPC_PORT"`
 	ReadinessPort      int    `env:"READINESS_PORT"`
 	EtcdPort           int    `env:"ETCD_PORT"`
+	HTTPPort           int    `env:"HTTP_PORT"`
+	ShotTransport      string `env:"SHOT_TRANSPORT" envDefault:"grpc"`
+	TLSEnabled         bool   `env:"TLS_ENABLED"`
+	TLSCertFile        string `env:"TLS_CERT_FILE"`
+	TLSKeyFile         string `env:"TLS_KEY_FILE"`
+	TLSCAFile          string `env:"TLS_CA_FILE"`
+
+	ShotSigningEnabled bool          `env:"SHOT_SIGNING_ENABLED"`
+	ShotKeyFile        string        `env:"SHOT_KEY_FILE"`
+	ShotPublicKeysFile string        `env:"SHOT_PUBLIC_KEYS_FILE"`
+	ShotMaxAge         time.Duration `env:"SHOT_MAX_AGE" envDefault:"30s"`
+
+	AntiCheatEnabled bool `env:"ANTI_CHEAT_ENABLED" envDefault:"true"`
+	AntiCheatBurst   int  `env:"ANTI_CHEAT_BURST" envDefault:"3"`
+
+	// DamageCoalescingWindow merges the shots received within it into one transaction when positive
+	DamageCoalescingWindow   time.Duration `env:"DAMAGE_COALESCING_WINDOW"`
+	DamageCoalescingMaxBatch int           `env:"DAMAGE_COALESCING_MAX_BATCH" envDefault:"32"`
+
+	// CowboyID overrides the id parsed from the StatefulSet hostname when not negative
+	CowboyID          int    `env:"COWBOY_ID" envDefault:"-1"`
+	PeerDiscovery     string `env:"PEER_DISCOVERY" envDefault:"statefulset"`
+	PeerGRPCAddresses string `env:"PEER_GRPC_ADDRESSES"`
+	PeerHTTPAddresses string `env:"PEER_HTTP_ADDRESSES"`
+	PeerSRVName       string `env:"PEER_SRV_NAME"`
+	AdvertiseHost     string `env:"ADVERTISE_HOST"`
+
+	CowboyControllerAppName string `env:"COWBOY_CONTROLLER_APP_NAME" envDefault:"cowboy-controller"`
+	GameID                  string `env:"GAME_ID"`
+
+	// ReadinessTimeoutPolicy decides whether the controller starts with the ready cowboys or aborts after ReadinessTimeout
+	ReadinessTimeout       time.Duration `env:"READINESS_TIMEOUT" envDefault:"5m"`
+	ReadinessTimeoutPolicy string        `env:"READINESS_TIMEOUT_POLICY" envDefault:"abort"`
+	// AutoStart starts the shootout once the cowboys are ready, otherwise it's started through the admin service
+	AutoStart bool `env:"AUTO_START" envDefault:"true"`
+	// StartQuorum is how many cowboys must acknowledge the shootout time, 0 means every ready cowboy
+	StartQuorum int `env:"START_QUORUM"`
+	// StartMode selects whether the controller pushes the start time to the cowboys or they pull it from the datastore
+	StartMode         string        `env:"START_MODE" envDefault:"push"`
+	StartPollInterval time.Duration `env:"START_POLL_INTERVAL" envDefault:"500ms"`
+	// Countdown is the time between the broadcast of the start time and the shootout
+	Countdown time.Duration `env:"COUNTDOWN" envDefault:"10s"`
+	// StartAt schedules the shootout of the first game at an RFC 3339 time, Schedule schedules the games on a cron
+	// schedule in UTC, e.g. "0 20 * * 5", a game is played for every scheduled time and gets its own id and result
+	StartAt  time.Time `env:"START_AT"`
+	Schedule string    `env:"SCHEDULE"`
+	// GamePhasePollInterval is how often the cowboys and the controller read the game phase, and the cowboys check for
+	// game master interventions
+	GamePhasePollInterval time.Duration `env:"GAME_PHASE_POLL_INTERVAL" envDefault:"250ms"`
+	// ClockSyncSamples is how many clock offset estimation exchanges the controller makes with every cowboy
+	ClockSyncSamples int `env:"CLOCK_SYNC_SAMPLES" envDefault:"5"`
+
+	// ShutdownPolicy decides whether a cowboy shut down mid-game forfeits or resumes after restarting, ShutdownTimeout
+	// bounds stopping the shootout and draining pending calls
+	ShutdownPolicy  string        `env:"SHUTDOWN_POLICY" envDefault:"resume"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+
+	// GameOverPolicy decides whether a cowboy keeps serving the result once the game is over or exits with the exit
+	// code of its outcome
+	GameOverPolicy  string `env:"GAME_OVER_POLICY" envDefault:"stay"`
+	WinnerExitCode  int    `env:"WINNER_EXIT_CODE" envDefault:"0"`
+	LoserExitCode   int    `env:"LOSER_EXIT_CODE" envDefault:"1"`
+	AbortedExitCode int    `env:"ABORTED_EXIT_CODE" envDefault:"2"`
+
+	// SeriesRounds is how many rounds are played, the cowboy with the most points over all rounds wins the series.
+	// SeriesPlacementPoints are awarded by placement in a round, the first to the winner, and SeriesKillPoints by kill
+	SeriesRounds          int           `env:"SERIES_ROUNDS" envDefault:"1"`
+	SeriesPlacementPoints []int         `env:"SERIES_PLACEMENT_POINTS" envDefault:"10,6,4,3,2,1"`
+	SeriesKillPoints      int           `env:"SERIES_KILL_POINTS" envDefault:"1"`
+	SeriesRoundInterval   time.Duration `env:"SERIES_ROUND_INTERVAL" envDefault:"10s"`
+
+	ClientRetryEnabled     bool          `env:"CLIENT_RETRY_ENABLED"`
+	ClientRetryBudgets     string        `env:"CLIENT_RETRY_BUDGETS" envDefault:"UNAVAILABLE=3"`
+	ClientRetryBackoff     time.Duration `env:"CLIENT_RETRY_BACKOFF" envDefault:"50ms"`
+	ClientDeadlinesEnabled bool          `env:"CLIENT_DEADLINES_ENABLED" envDefault:"true"`
+	ClientDefaultDeadline  time.Duration `env:"CLIENT_DEFAULT_DEADLINE" envDefault:"2s"`
+	ClientMethodDeadlines  string        `env:"CLIENT_METHOD_DEADLINES"`
+	ClientMetadataEnabled  bool          `env:"CLIENT_METADATA_ENABLED" envDefault:"true"`
+	ClientLoggingEnabled   bool          `env:"CLIENT_LOGGING_ENABLED"`
+
+	DamageStreamWindow    int           `env:"DAMAGE_STREAM_WINDOW" envDefault:"64"`
+	TargetRefreshInterval time.Duration `env:"TARGET_REFRESH_INTERVAL" envDefault:"1s"`
+
+	ServerLoggingEnabled    bool `env:"SERVER_LOGGING_ENABLED" envDefault:"true"`
+	ServerRecoveryEnabled   bool `env:"SERVER_RECOVERY_ENABLED" envDefault:"true"`
+	ServerValidationEnabled bool `env:"SERVER_VALIDATION_ENABLED" envDefault:"true"`
+	ServerMetricsEnabled    bool `env:"SERVER_METRICS_ENABLED" envDefault:"true"`
+
+	LoadSheddingEnabled       bool          `env:"LOAD_SHEDDING_ENABLED" envDefault:"true"`
+	LoadSheddingInitialLimit  int           `env:"LOAD_SHEDDING_INITIAL_LIMIT" envDefault:"16"`
+	LoadSheddingMinLimit      int           `env:"LOAD_SHEDDING_MIN_LIMIT" envDefault:"2"`
+	LoadSheddingMaxLimit      int           `env:"LOAD_SHEDDING_MAX_LIMIT" envDefault:"256"`
+	LoadSheddingMaxQueue      int           `env:"LOAD_SHEDDING_MAX_QUEUE" envDefault:"64"`
+	LoadSheddingTargetLatency time.Duration `env:"LOAD_SHEDDING_TARGET_LATENCY" envDefault:"50ms"`
 }
 
 func InitLogger() *zap.Logger {
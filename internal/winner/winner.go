@@ -0,0 +1,185 @@
+package winner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ErrNoWinner        = utils.ConstError("no winner declared")
+	ErrAlreadyDeclared = utils.ConstError("another cowboy was declared the winner")
+	ErrNotLastAlive    = utils.ConstError("not the last cowboy alive")
+)
+
+// Key holds the winner record
+const Key = "winner"
+
+// Record is the result of a finished game
+type Record struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Health     int       `json:"health"`
+	DeclaredAt time.Time `json:"declared_at"`
+}
+
+// Declare records the cowboy as the winner and finishes the game in one transaction. It only succeeds if the game is
+// running, no winner was declared yet and the cowboy is the only one of the replicas alive, cowboys which didn't join
+// the game don't count. Declaring the same winner again succeeds, so that a restarted winner can confirm its win
+func Declare(ctx context.Context, db datastore.Datastore, replicas int, id int, name string) (Record, error) {
+	healths, err := db.GetPrefix(ctx, utils.CowboyKeyPrefix)
+	if err != nil && !errors.Is(err, datastore.ErrKeyNotFound) {
+		return Record{}, fmt.Errorf("get cowboy health: %w", err)
+	}
+
+	record := Record{ID: id, Name: name, DeclaredAt: time.Now().UTC()}
+
+	var cmps []datastore.Cmp
+
+	joined := make(map[int]bool, len(healths))
+
+	for key, value := range healths {
+		cowboyID, err := strconv.Atoi(strings.TrimPrefix(key, utils.CowboyKeyPrefix))
+		if err != nil {
+			return Record{}, fmt.Errorf("convert cowboy id to int: %w", err)
+		}
+
+		joined[cowboyID] = true
+
+		health, err := strconv.Atoi(value)
+		if err != nil {
+			return Record{}, fmt.Errorf("convert cowboy health to int: %w", err)
+		}
+
+		if cowboyID == id {
+			record.Health = health
+		}
+
+		// the compared values are the ones we checked, so that nobody is shot or revived in between
+		cmps = append(cmps, datastore.Compare(key, "=", value))
+
+		if (cowboyID == id) != (health > 0) {
+			return confirm(ctx, db, id, fmt.Errorf("%w: cowboy %d has %d health", ErrNotLastAlive, cowboyID, health))
+		}
+	}
+
+	if !joined[id] {
+		return Record{}, fmt.Errorf("%w: cowboy %d didn't join the game", ErrNotLastAlive, id)
+	}
+
+	for cowboyID := 0; cowboyID < replicas; cowboyID++ {
+		if !joined[cowboyID] {
+			cmps = append(cmps, datastore.CompareAbsent(utils.CowboyKeyPrefix+strconv.Itoa(cowboyID)))
+		}
+	}
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, fmt.Errorf("marshal winner record: %w", err)
+	}
+
+	cmps = append(cmps, datastore.CompareAbsent(Key))
+
+	err = gamephase.TransitionIf(ctx, db, gamephase.Running, gamephase.Finished, cmps, datastore.OpPut(Key, string(value)))
+	if errors.Is(err, gamephase.ErrPhaseChanged) {
+		return confirm(ctx, db, id, err)
+	}
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	return record, nil
+}
+
+// confirm returns the winner record if the cowboy was already declared the winner, otherwise ErrAlreadyDeclared if
+// another cowboy was or err if nobody was
+func confirm(ctx context.Context, db datastore.Datastore, id int, err error) (Record, error) {
+	record, getErr := Get(ctx, db)
+	switch {
+	case errors.Is(getErr, ErrNoWinner):
+		return Record{}, err
+	case getErr != nil:
+		return Record{}, getErr
+	case record.ID != id:
+		return Record{}, fmt.Errorf("%w: cowboy %d", ErrAlreadyDeclared, record.ID)
+	default:
+		return record, nil
+	}
+}
+
+// Get returns the winner record, ErrNoWinner if no winner was declared
+func Get(ctx context.Context, db datastore.Datastore) (Record, error) {
+	value, err := db.Get(ctx, Key)
+	if errors.Is(err, datastore.ErrKeyNotFound) {
+		return Record{}, ErrNoWinner
+	}
+
+	if err != nil {
+		return Record{}, fmt.Errorf("get winner record: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return Record{}, fmt.Errorf("unmarshal winner record: %w", err)
+	}
+
+	return record, nil
+}
+
+// Result is the outcome of the game served to anyone asking
+type Result struct {
+	GamePhase gamephase.Phase `json:"game_phase"`
+	// Winner is only set once the game finished
+	Winner *Record `json:"winner,omitempty"`
+}
+
+// GetResult returns the game phase and the winner record if there is one
+func GetResult(ctx context.Context, db datastore.Datastore) (Result, error) {
+	phase, err := gamephase.Get(ctx, db)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{GamePhase: phase}
+
+	record, err := Get(ctx, db)
+	switch {
+	case errors.Is(err, ErrNoWinner):
+	case err != nil:
+		return Result{}, err
+	default:
+		result.Winner = &record
+	}
+
+	return result, nil
+}
+
+// Handler serves the result of the game as JSON
+func Handler(logger *zap.Logger, db datastore.Datastore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := GetResult(r.Context(), db)
+		if err != nil {
+			logger.Warn("get game result", zap.Error(err))
+			http.Error(w, "get game result", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Warn("write game result", zap.Error(err))
+		}
+	}
+}
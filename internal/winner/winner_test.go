@@ -0,0 +1,103 @@
+package winner_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wildwest/internal/datastore"
+	"wildwest/internal/gamephase"
+	"wildwest/internal/utils"
+	"wildwest/internal/winner"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestDeclare(t *testing.T) {
+	tests := []struct {
+		name          string
+		gamePhase     gamephase.Phase
+		healths       map[string]string
+		declared      *winner.Record
+		wantErr       error
+		wantGamePhase gamephase.Phase
+		wantWinner    int
+	}{
+		{"last alive", gamephase.Running, map[string]string{"0": "0", "1": "4", "2": "0"}, nil, nil, gamephase.Finished, 1},
+		{"others not joined", gamephase.Running, map[string]string{"1": "4"}, nil, nil, gamephase.Finished, 1},
+		{"another alive", gamephase.Running, map[string]string{"0": "0", "1": "4", "2": "1"}, nil, winner.ErrNotLastAlive, gamephase.Running, -1},
+		{"dead", gamephase.Running, map[string]string{"0": "0", "1": "0", "2": "3"}, nil, winner.ErrNotLastAlive, gamephase.Running, -1},
+		{"game paused", gamephase.Paused, map[string]string{"0": "0", "1": "4", "2": "0"}, nil, gamephase.ErrPhaseChanged, gamephase.Paused, -1},
+		{"another declared", gamephase.Finished, map[string]string{"0": "0", "1": "4", "2": "0"}, &winner.Record{ID: 2}, winner.ErrAlreadyDeclared, gamephase.Finished, 2},
+		{"declared before restarting", gamephase.Finished, map[string]string{"0": "0", "1": "4", "2": "0"}, &winner.Record{ID: 1}, nil, gamephase.Finished, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			fakeDatastore := datastore.NewFakeClient()
+			assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(tc.gamePhase)))
+
+			for id, health := range tc.healths {
+				assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+id, health))
+			}
+
+			if tc.declared != nil {
+				value, err := json.Marshal(tc.declared)
+				assert.NoError(t, err)
+				assert.NoError(t, fakeDatastore.Put(ctx, winner.Key, string(value)))
+			}
+
+			// execute
+			record, err := winner.Declare(ctx, fakeDatastore, 3, 1, "Bill")
+
+			// verify
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr == nil {
+				assert.Equal(t, 1, record.ID)
+			}
+
+			gamePhase, err := gamephase.Get(ctx, fakeDatastore)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantGamePhase, gamePhase)
+
+			stored, err := winner.Get(ctx, fakeDatastore)
+			if tc.wantWinner < 0 {
+				assert.ErrorIs(t, err, winner.ErrNoWinner)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantWinner, stored.ID)
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	// setup
+	ctx := context.Background()
+	fakeDatastore := datastore.NewFakeClient()
+	assert.NoError(t, fakeDatastore.Put(ctx, gamephase.Key, string(gamephase.Running)))
+	assert.NoError(t, fakeDatastore.Put(ctx, utils.CowboyKeyPrefix+"0", "7"))
+
+	_, err := winner.Declare(ctx, fakeDatastore, 1, 0, "John")
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	// execute
+	winner.Handler(zap.NewNop(), fakeDatastore).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/result", nil))
+
+	// verify
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var result winner.Result
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+	assert.Equal(t, gamephase.Finished, result.GamePhase)
+	assert.Equal(t, "John", result.Winner.Name)
+	assert.Equal(t, 7, result.Winner.Health)
+}